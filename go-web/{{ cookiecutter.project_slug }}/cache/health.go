@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"context"
+
+	"exampleproj/internal/health"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewHealthCheck fails the readiness while the redis is unreachable
+func NewHealthCheck(rdb *redis.Client) health.Check {
+	return health.Check{
+		Name: "redis",
+		Probe: func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		},
+	}
+}
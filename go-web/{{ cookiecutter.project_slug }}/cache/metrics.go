@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"exampleproj/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// Metrics times the commands of a redis client, it's a redis.Hook
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	dials    *prometheus.CounterVec
+}
+
+// NewMetrics hooks the metrics into rdb
+func NewMetrics(rdb *redis.Client) *Metrics {
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "redis",
+			Name:      "command_duration_seconds",
+			Help:      "The latency of the redis commands by command and status, the pipelines are one command.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command", "status"}),
+		dials: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "redis",
+			Name:      "dials_total",
+			Help:      "The connections opened to redis by status.",
+		}, []string{"status"}),
+	}
+	rdb.AddHook(m)
+	return m
+}
+
+// status is ok for the commands which succeeded, a missing key included
+func status(err error) string {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return "ok"
+	}
+	return "error"
+}
+
+func (m *Metrics) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		m.dials.WithLabelValues(status(err)).Inc()
+		return conn, err
+	}
+}
+
+func (m *Metrics) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		m.duration.WithLabelValues(cmd.Name(), status(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (m *Metrics) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		m.duration.WithLabelValues("pipeline", status(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.dials.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.dials.Collect(ch)
+}
+
+var (
+	_ redis.Hook           = (*Metrics)(nil)
+	_ prometheus.Collector = (*Metrics)(nil)
+)
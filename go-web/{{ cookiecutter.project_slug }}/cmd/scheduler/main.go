@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"exampleproj/config"
 	"exampleproj/internal/app"
+	"exampleproj/internal/shutdown"
 	"exampleproj/internal/tasks"
 
 	"github.com/hibiken/asynq"
@@ -26,8 +28,15 @@ func RegisterTasks(scheduler *asynq.Scheduler) {
 	scheduler.Register("@every 1s", task)
 }
 
-func RunScheduler(scheduler *asynq.Scheduler) error {
-	return scheduler.Run()
+// RunScheduler starts the scheduler with the app, it stops enqueuing in the
+// workers phase of the shutdown
+func RunScheduler(lc fx.Lifecycle, scheduler *asynq.Scheduler, coordinator *shutdown.Coordinator) {
+	lc.Append(fx.StartHook(scheduler.Start))
+
+	coordinator.Register(shutdown.PhaseWorkers, "asynq scheduler", func(ctx context.Context) error {
+		scheduler.Shutdown()
+		return nil
+	})
 }
 
 func main() {
@@ -37,7 +46,10 @@ func main() {
 		fx.Provide(app.NewLogger),
 		fx.Provide(tasks.NewScheduler),
 		fx.Invoke(RegisterTasks),
+		shutdown.Module,
 		fx.Invoke(RunScheduler),
+		fx.Invoke(shutdown.Install),
+		fx.StopTimeout(shutdown.StopTimeout),
 	).Run()
 
 }
@@ -2,7 +2,10 @@ package main
 
 import (
 	"exampleproj/config"
+	"exampleproj/internal/admin"
 	"exampleproj/internal/app"
+	"exampleproj/internal/metrics"
+	"exampleproj/internal/shutdown"
 	"exampleproj/internal/tasks"
 	"context"
 	"fmt"
@@ -12,38 +15,38 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewAsyncQMux(taskHandlers map[string]func(context.Context, *asynq.Task) error) *asynq.ServeMux {
+func NewAsyncQMux(taskHandlers map[string]func(context.Context, *asynq.Task) error, taskMetrics *tasks.TaskMetrics) *asynq.ServeMux {
 	mux := asynq.NewServeMux()
+	mux.Use(taskMetrics.Middleware)
 	for t, h := range taskHandlers {
 		mux.HandleFunc(t, h)
 	}
 	return mux
 }
 
-func NewWrokerServer(lc fx.Lifecycle, config *config.Config, sugar *zap.SugaredLogger, mux *asynq.ServeMux) *asynq.Server {
+// NewWrokerServer starts the asynq server with the app, the signals are
+// handled by fx and the running tasks get cfg.SHUTDOWN.TIMEOUT to finish
+func NewWrokerServer(lc fx.Lifecycle, config *config.Config, sugar *zap.SugaredLogger, mux *asynq.ServeMux, coordinator *shutdown.Coordinator) *asynq.Server {
 	srv := asynq.NewServer(
 		asynq.RedisClientOpt{Addr: fmt.Sprintf("%s:%s", config.REDIS.ADDR, config.REDIS.PORT)},
 		asynq.Config{
-			Concurrency: 1,
-			Logger:      sugar,
+			Concurrency:     1,
+			Logger:          sugar,
+			ShutdownTimeout: config.SHUTDOWN.TIMEOUT,
 		},
 	)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go func() {
-				if err := srv.Run(mux); err != nil {
-					sugar.Fatal(err)
-				}
-			}()
-
-			return nil
-		},
-		OnStop: func(ctx context.Context) error {
-			srv.Shutdown()
-			return nil
+			return srv.Start(mux)
 		}})
 
+	coordinator.Register(shutdown.PhaseWorkers, "asynq server", func(ctx context.Context) error {
+		// Shutdown waits for the running tasks up to ShutdownTimeout
+		srv.Shutdown()
+		return nil
+	})
+
 	return srv
 }
 
@@ -55,7 +58,17 @@ func main() {
 		fx.Provide(app.NewLogger),
 		fx.Provide(config.NewViper),
 		fx.Provide(NewWrokerServer),
-		fx.Invoke(func(*asynq.Server) {}),
+		fx.Provide(
+			tasks.NewTaskMetrics,
+			metrics.Expose[*tasks.TaskMetrics](),
+			metrics.AsCollector(app.NewPythCollector),
+		),
+		shutdown.Module,
+		metrics.Module,
+		admin.Module,
+		fx.Invoke(func(*asynq.Server, *admin.Server) {}),
+		fx.Invoke(shutdown.Install),
+		fx.StopTimeout(shutdown.StopTimeout),
 	).Run()
 
 }
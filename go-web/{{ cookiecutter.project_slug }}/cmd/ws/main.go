@@ -3,7 +3,10 @@ package main
 import (
 	"exampleproj/cache"
 	"exampleproj/config"
+	"exampleproj/internal/admin"
 	"exampleproj/internal/app"
+	"exampleproj/internal/metrics"
+	"exampleproj/internal/shutdown"
 	"exampleproj/routers"
 	"exampleproj/routers/handlers"
 	"net/http"
@@ -15,20 +18,34 @@ func main() {
 
 	fx.New(
 		fx.Provide(
-			app.NewHTTPServer,
+			fx.Annotate(
+				app.NewHTTPServer,
+				fx.ParamTags(``, ``, ``, ``, ``, `optional:"true"`),
+			),
 			fx.Annotate(
 				routers.NewRouter,
-				fx.ParamTags(`group:"handlers"`),
+				fx.ParamTags(`group:"handlers"`, `optional:"true"`, `optional:"true"`, `optional:"true"`, `optional:"true"`),
 			),
 
+			app.NewHub,
 			routers.AsRoute(handlers.NewWebsocketHandler),
+
+			metrics.NewHTTP,
+			metrics.Expose[*metrics.HTTP](),
+			metrics.AsCollector(app.NewHubCollector),
+			metrics.AsCollector(cache.NewMetrics),
 		),
 
 		fx.Provide(config.NewViper),
 		fx.Provide(config.NewConfig),
 		fx.Provide(cache.NewRedis),
 		fx.Provide(app.NewLogger),
-		fx.Invoke(func(*http.Server) {}),
+		shutdown.Module,
+		metrics.Module,
+		admin.Module,
+		fx.Invoke(func(*http.Server, *admin.Server) {}),
+		fx.Invoke(shutdown.Install),
+		fx.StopTimeout(shutdown.StopTimeout),
 	).Run()
 
 }
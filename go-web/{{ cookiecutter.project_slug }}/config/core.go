@@ -2,6 +2,7 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
@@ -22,11 +23,45 @@ const (
 	Prod    Env = "prod"
 )
 
+// LocalAuthSecret is the default AUTH.SECRET, it's public so it's refused
+// outside of the local env
+const LocalAuthSecret = "local-secret"
+
+// Config is the configuration of the apps, the fields tagged secret:"true"
+// are hidden by Redacted
 type Config struct {
 	App struct {
 		Addr string
 		Port string
 		Env  Env
+		// ERROR_FORMAT is the default error document, "basic" or "problem"
+		// for RFC 7807, clients may still pick one through their Accept header
+		ERROR_FORMAT string `mapstructure:"error_format"`
+
+		// SOCKET is the path of a unix socket to listen on instead of
+		// Addr:Port, ex. behind a reverse proxy on the same host
+		SOCKET string `mapstructure:"socket"`
+
+		// the timeouts of the http server, 0 disables them. WRITE_TIMEOUT
+		// has to outlast MIDDLEWARE.TIMEOUT for the handlers to answer
+		READ_TIMEOUT        time.Duration `mapstructure:"read_timeout"`
+		READ_HEADER_TIMEOUT time.Duration `mapstructure:"read_header_timeout"`
+		WRITE_TIMEOUT       time.Duration `mapstructure:"write_timeout"`
+		IDLE_TIMEOUT        time.Duration `mapstructure:"idle_timeout"`
+		MAX_HEADER_BYTES    int           `mapstructure:"max_header_bytes"`
+
+		// TLS serves https, and HTTP/2, when both files are set. The
+		// certificate is reloaded when the files change on disk
+		TLS struct {
+			CERT_FILE string `mapstructure:"cert_file"`
+			KEY_FILE  string `mapstructure:"key_file"`
+			// RELOAD_INTERVAL is how often the files are checked
+			RELOAD_INTERVAL time.Duration `mapstructure:"reload_interval"`
+		} `mapstructure:"tls"`
+
+		// H2C serves HTTP/2 without TLS, meant for local or behind a proxy
+		// speaking h2c
+		H2C bool `mapstructure:"h2c"`
 	} `mapstructure:"app"`
 
 	DB struct {
@@ -35,7 +70,16 @@ type Config struct {
 		HOST     string   `mapstructure:"host"`
 		PORT     string   `mapstructure:"port"`
 		USER     string   `mapstructure:"user"`
-		PASSWORD string   `mapstructure:"password"`
+		PASSWORD string   `mapstructure:"password" secret:"true"`
+
+		// POOL tunes the pgxpool used by the postgres engine
+		POOL struct {
+			MAX_CONNS           int32         `mapstructure:"max_conns"`
+			MIN_CONNS           int32         `mapstructure:"min_conns"`
+			MAX_CONN_LIFETIME   time.Duration `mapstructure:"max_conn_lifetime"`
+			MAX_CONN_IDLE_TIME  time.Duration `mapstructure:"max_conn_idle_time"`
+			HEALTH_CHECK_PERIOD time.Duration `mapstructure:"health_check_period"`
+		} `mapstructure:"pool"`
 	} `mapstructure:"db"`
 
 	REDIS struct {
@@ -43,9 +87,110 @@ type Config struct {
 		PORT string `mapstructure:"port"`
 	} `mapstructure:"redis"`
 
+	AUTH struct {
+		// SECRET signs the access tokens, always override it outside local
+		SECRET            string        `mapstructure:"secret" secret:"true"`
+		ISSUER            string        `mapstructure:"issuer"`
+		ACCESS_TOKEN_TTL  time.Duration `mapstructure:"access_token_ttl"`
+		REFRESH_TOKEN_TTL time.Duration `mapstructure:"refresh_token_ttl"`
+
+		// ADMIN_EMAILS are granted the admin role on start, the users have
+		// to be registered first
+		ADMIN_EMAILS []string `mapstructure:"admin_emails"`
+	} `mapstructure:"auth"`
+
+	// MIDDLEWARE toggles the middlewares routers.NewRouter chains
+	MIDDLEWARE struct {
+		ACCESS_LOG bool `mapstructure:"access_log"`
+		RECOVER    bool `mapstructure:"recover"`
+
+		// TRUSTED_PROXIES are the ips or cidrs, ex. 10.0.0.0/8, whose
+		// X-Forwarded-For and X-Real-IP headers give the client ip. The
+		// other peers are the client.
+		TRUSTED_PROXIES []string `mapstructure:"trusted_proxies"`
+
+		// TIMEOUT bounds the requests, 0 disables it. ROUTE_TIMEOUTS override
+		// it per path prefix as prefix=duration, ex. /ws=0
+		TIMEOUT        time.Duration `mapstructure:"timeout"`
+		ROUTE_TIMEOUTS []string      `mapstructure:"route_timeouts"`
+
+		CORS struct {
+			ENABLED           bool     `mapstructure:"enabled"`
+			ALLOWED_ORIGINS   []string `mapstructure:"allowed_origins"`
+			ALLOWED_METHODS   []string `mapstructure:"allowed_methods"`
+			ALLOWED_HEADERS   []string `mapstructure:"allowed_headers"`
+			EXPOSED_HEADERS   []string `mapstructure:"exposed_headers"`
+			ALLOW_CREDENTIALS bool     `mapstructure:"allow_credentials"`
+			MAX_AGE           int      `mapstructure:"max_age"`
+		} `mapstructure:"cors"`
+
+		// COMPRESS encodes the responses with brotli or gzip, LEVEL is shared
+		// by both
+		COMPRESS struct {
+			ENABLED bool `mapstructure:"enabled"`
+			LEVEL   int  `mapstructure:"level"`
+		} `mapstructure:"compress"`
+	} `mapstructure:"middleware"`
+
+	// RATE_LIMIT limits the requests of every client, the authenticated users
+	// by id and the others by ip
+	RATE_LIMIT struct {
+		ENABLED bool `mapstructure:"enabled"`
+		// STORE is redis, or memory for a single instance and the tests
+		STORE string `mapstructure:"store"`
+		// LIMIT is requests/window, ex. 100/1m. ROUTES override it per path
+		// prefix as prefix=limit, ex. /auth=10/1m, a 0 limit disables it
+		LIMIT  string   `mapstructure:"limit"`
+		ROUTES []string `mapstructure:"routes"`
+	} `mapstructure:"rate_limit"`
+
+	// IDEMPOTENCY replays the responses of the POST requests retried with
+	// the same Idempotency-Key header
+	IDEMPOTENCY struct {
+		ENABLED bool `mapstructure:"enabled"`
+		// TTL is how long the responses are kept for the retries
+		TTL time.Duration `mapstructure:"ttl"`
+		// LOCK_TTL bounds the time a request holds its key, the retries
+		// meanwhile are answered a 409
+		LOCK_TTL time.Duration `mapstructure:"lock_ttl"`
+		// MAX_BODY_BYTES caps the bodies read to fingerprint the requests
+		MAX_BODY_BYTES int64 `mapstructure:"max_body_bytes"`
+		// ROUTES are the path prefixes the keys are honored on, the other
+		// routes, ex. /auth whose responses carry tokens, are never kept
+		ROUTES []string `mapstructure:"routes"`
+	} `mapstructure:"idempotency"`
+
+	// HEALTH tunes the checks of the readiness probe
+	HEALTH struct {
+		// TIMEOUT bounds the checks which don't set their own
+		TIMEOUT time.Duration `mapstructure:"timeout"`
+		// CACHE_TTL is how long the results are reused by the probes
+		CACHE_TTL time.Duration `mapstructure:"cache_ttl"`
+	} `mapstructure:"health"`
+
+	// ADMIN serves the debug endpoints, pprof, expvar, the metrics, the
+	// config and the log level, apart from the public port. Keep it bound to
+	// a private address
+	ADMIN struct {
+		ENABLED bool   `mapstructure:"enabled"`
+		ADDR    string `mapstructure:"addr"`
+		PORT    string `mapstructure:"port"`
+	} `mapstructure:"admin"`
+
+	// SHUTDOWN bounds the graceful shutdown of the apps
+	SHUTDOWN struct {
+		// DRAIN_DELAY is how long the readiness fails before the servers
+		// stop accepting, the time for the orchestrator to notice it
+		DRAIN_DELAY time.Duration `mapstructure:"drain_delay"`
+		// TIMEOUT bounds the in-flight requests, the websockets and the
+		// running tasks
+		TIMEOUT time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"shutdown"`
+
 	WEB3 struct {
-		BLASTRPC_URL      string `mapstructure:"blastrpc_url"`
-		BLASTSCAN_API_KEY string `mapstructure:"blastscan_api_key"`
+		// the rpc urls usually embed an api key
+		BLASTRPC_URL      string `mapstructure:"blastrpc_url" secret:"true"`
+		BLASTSCAN_API_KEY string `mapstructure:"blastscan_api_key" secret:"true"`
 		PYTH_API_HOST     string `mapstructure:"pyth_api_host"`
 	}
 }
@@ -66,15 +211,66 @@ func NewViper(lc fx.Lifecycle) *viper.Viper {
 	vp.SetDefault("app.addr", "0.0.0.0")
 	vp.SetDefault("app.port", "8080")
 	vp.SetDefault("app.env", Local)
+	vp.SetDefault("app.error_format", "basic")
+	vp.SetDefault("app.socket", "")
+	vp.SetDefault("app.read_timeout", 30*time.Second)
+	vp.SetDefault("app.read_header_timeout", 10*time.Second)
+	vp.SetDefault("app.write_timeout", 90*time.Second)
+	vp.SetDefault("app.idle_timeout", 2*time.Minute)
+	vp.SetDefault("app.max_header_bytes", 1<<20)
+	vp.SetDefault("app.tls.cert_file", "")
+	vp.SetDefault("app.tls.key_file", "")
+	vp.SetDefault("app.tls.reload_interval", 10*time.Second)
+	vp.SetDefault("app.h2c", false)
 	vp.SetDefault("db.engine", SQLite)
 	vp.SetDefault("db.host", "localhost")
 	vp.SetDefault("db.port", "5432")
 	vp.SetDefault("db.user", "postgres")
 	vp.SetDefault("db.name", "song")
 	vp.SetDefault("db.password", "postgres")
+	vp.SetDefault("db.pool.max_conns", 10)
+	vp.SetDefault("db.pool.min_conns", 0)
+	vp.SetDefault("db.pool.max_conn_lifetime", time.Hour)
+	vp.SetDefault("db.pool.max_conn_idle_time", 30*time.Minute)
+	vp.SetDefault("db.pool.health_check_period", time.Minute)
 	vp.SetDefault("redis.addr", "redis")
 	vp.SetDefault("redis.port", "6379")
+	vp.SetDefault("auth.secret", LocalAuthSecret)
+	vp.SetDefault("auth.issuer", "exampleproj")
+	vp.SetDefault("auth.access_token_ttl", 15*time.Minute)
+	vp.SetDefault("auth.refresh_token_ttl", 7*24*time.Hour)
+	vp.SetDefault("auth.admin_emails", []string{})
 	vp.SetDefault("web3.pyth_api_host", "https://hermes.pyth.network")
+	vp.SetDefault("middleware.access_log", true)
+	vp.SetDefault("middleware.recover", true)
+	vp.SetDefault("middleware.trusted_proxies", []string{})
+	vp.SetDefault("middleware.timeout", time.Minute)
+	vp.SetDefault("middleware.route_timeouts", []string{"/ws=0"})
+	vp.SetDefault("middleware.cors.enabled", false)
+	vp.SetDefault("middleware.cors.allowed_origins", []string{"*"})
+	vp.SetDefault("middleware.cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	vp.SetDefault("middleware.cors.allowed_headers", []string{"Accept", "Accept-Language", "Authorization", "Content-Type", "Idempotency-Key"})
+	vp.SetDefault("middleware.cors.exposed_headers", []string{"Link", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Idempotent-Replayed"})
+	vp.SetDefault("middleware.cors.allow_credentials", false)
+	vp.SetDefault("middleware.cors.max_age", 300)
+	vp.SetDefault("middleware.compress.enabled", true)
+	vp.SetDefault("middleware.compress.level", 5)
+	vp.SetDefault("rate_limit.enabled", true)
+	vp.SetDefault("rate_limit.store", "redis")
+	vp.SetDefault("rate_limit.limit", "300/1m")
+	vp.SetDefault("rate_limit.routes", []string{"/auth=20/1m", "/health=0", "/livez=0", "/readyz=0"})
+	vp.SetDefault("idempotency.enabled", true)
+	vp.SetDefault("idempotency.ttl", 24*time.Hour)
+	vp.SetDefault("idempotency.lock_ttl", time.Minute)
+	vp.SetDefault("idempotency.max_body_bytes", 1<<20)
+	vp.SetDefault("idempotency.routes", []string{"/users", "/authors"})
+	vp.SetDefault("health.timeout", 2*time.Second)
+	vp.SetDefault("health.cache_ttl", time.Second)
+	vp.SetDefault("admin.enabled", true)
+	vp.SetDefault("admin.addr", "127.0.0.1")
+	vp.SetDefault("admin.port", "9090")
+	vp.SetDefault("shutdown.drain_delay", 5*time.Second)
+	vp.SetDefault("shutdown.timeout", 30*time.Second)
 
 	replacer := strings.NewReplacer(".", "_")
 	vp.SetEnvKeyReplacer(replacer)
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue is the placeholder of the secrets in Config.Redacted
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns the config keyed like the viper keys, ex. app.port, with
+// the secrets replaced so it's safe to show
+func (c *Config) Redacted() map[string]any {
+	return redact(reflect.ValueOf(*c)).(map[string]any)
+}
+
+func redact(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := map[string]any{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+
+			value := v.Field(i)
+			if field.Tag.Get("secret") == "true" {
+				if !value.IsZero() {
+					out[name] = RedactedValue
+				} else {
+					out[name] = ""
+				}
+				continue
+			}
+			out[name] = redact(value)
+		}
+		return out
+	case reflect.Int64:
+		// the durations read better as 1m0s than in nanoseconds
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String()
+		}
+	}
+	return v.Interface()
+}
@@ -13,22 +13,28 @@ import (
 
 const createAuthor = `-- name: CreateAuthor :one
 INSERT INTO authors (
-  name, bio
+  name, bio, user_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
-RETURNING id, name, bio
+RETURNING id, name, bio, user_id
 `
 
 type CreateAuthorParams struct {
-	Name string
-	Bio  pgtype.Text
+	Name   string
+	Bio    pgtype.Text
+	UserID int32
 }
 
 func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error) {
-	row := q.db.QueryRow(ctx, createAuthor, arg.Name, arg.Bio)
+	row := q.db.QueryRow(ctx, createAuthor, arg.Name, arg.Bio, arg.UserID)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name, &i.Bio)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
 	return i, err
 }
 
@@ -43,24 +49,190 @@ func (q *Queries) DeleteAuthor(ctx context.Context, id int32) error {
 }
 
 const getAuthor = `-- name: GetAuthor :one
-SELECT id, name, bio FROM authors
+SELECT id, name, bio, user_id FROM authors
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetAuthor(ctx context.Context, id int32) (Author, error) {
 	row := q.db.QueryRow(ctx, getAuthor, id)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name, &i.Bio)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
 	return i, err
 }
 
-const listAuthors = `-- name: ListAuthors :many
-SELECT id, name, bio FROM authors
-ORDER BY name
+const listAuthorsByID = `-- name: ListAuthorsByID :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY id
+LIMIT $2::bigint
+`
+
+type ListAuthorsByIDParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByID(ctx context.Context, arg ListAuthorsByIDParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByID, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDAfter = `-- name: ListAuthorsByIDAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND id > $2::int
+ORDER BY id
+LIMIT $3::bigint
+`
+
+type ListAuthorsByIDAfterParams struct {
+	NamePrefix string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDAfter(ctx context.Context, arg ListAuthorsByIDAfterParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByIDAfter, arg.NamePrefix, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDDesc = `-- name: ListAuthorsByIDDesc :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT $2::bigint
+`
+
+type ListAuthorsByIDDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDDesc(ctx context.Context, arg ListAuthorsByIDDescParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByIDDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDDescAfter = `-- name: ListAuthorsByIDDescAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND id < $2::int
+ORDER BY id DESC
+LIMIT $3::bigint
+`
+
+type ListAuthorsByIDDescAfterParams struct {
+	NamePrefix string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDDescAfter(ctx context.Context, arg ListAuthorsByIDDescAfterParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByIDDescAfter, arg.NamePrefix, arg.BeforeID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByName = `-- name: ListAuthorsByName :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT $2::bigint
 `
 
-func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
-	rows, err := q.db.Query(ctx, listAuthors)
+type ListAuthorsByNameParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+// the List*By* queries are the first keyset pages of internal/pagination,
+// the List*By*After ones the pages after the cursor
+func (q *Queries) ListAuthorsByName(ctx context.Context, arg ListAuthorsByNameParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByName, arg.NamePrefix, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +240,12 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	var items []Author
 	for rows.Next() {
 		var i Author
-		if err := rows.Scan(&i.ID, &i.Name, &i.Bio); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -79,12 +256,139 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	return items, nil
 }
 
-const updateAuthor = `-- name: UpdateAuthor :exec
+const listAuthorsByNameAfter = `-- name: ListAuthorsByNameAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND (name, id) > ($2::text, $3::int)
+ORDER BY name, id
+LIMIT $4::bigint
+`
+
+type ListAuthorsByNameAfterParams struct {
+	NamePrefix string
+	AfterName  string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameAfter(ctx context.Context, arg ListAuthorsByNameAfterParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByNameAfter,
+		arg.NamePrefix,
+		arg.AfterName,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByNameDesc = `-- name: ListAuthorsByNameDesc :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT $2::bigint
+`
+
+type ListAuthorsByNameDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameDesc(ctx context.Context, arg ListAuthorsByNameDescParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByNameDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByNameDescAfter = `-- name: ListAuthorsByNameDescAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND (name, id) < ($2::text, $3::int)
+ORDER BY name DESC, id DESC
+LIMIT $4::bigint
+`
+
+type ListAuthorsByNameDescAfterParams struct {
+	NamePrefix string
+	BeforeName string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameDescAfter(ctx context.Context, arg ListAuthorsByNameDescAfterParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsByNameDescAfter,
+		arg.NamePrefix,
+		arg.BeforeName,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAuthor = `-- name: UpdateAuthor :one
 UPDATE authors
 set name = $1,
 bio = $2
 WHERE id = $3
-RETURNING id, name, bio
+RETURNING id, name, bio, user_id
 `
 
 type UpdateAuthorParams struct {
@@ -93,7 +397,14 @@ type UpdateAuthorParams struct {
 	ID   int32
 }
 
-func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) error {
-	_, err := q.db.Exec(ctx, updateAuthor, arg.Name, arg.Bio, arg.ID)
-	return err
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error) {
+	row := q.db.QueryRow(ctx, updateAuthor, arg.Name, arg.Bio, arg.ID)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
+	return i, err
 }
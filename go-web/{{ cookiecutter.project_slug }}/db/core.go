@@ -6,8 +6,9 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/mattn/go-sqlite3"
 	"go.uber.org/fx"
 
@@ -49,27 +50,95 @@ func GetPostgresqlDSN(config *config.Config) string {
 	)
 }
 
-func NewPostgresqlDB(lc fx.Lifecycle, config *config.Config) *pgx.Conn {
-	ctx := context.Background()
-	dsn := GetPostgresqlDSN(config)
-	conn, err := pgx.Connect(ctx, dsn)
+// NewPostgresqlPoolConfig builds the pgxpool config from the application config
+func NewPostgresqlPoolConfig(config *config.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(GetPostgresqlDSN(config))
+	if err != nil {
+		return nil, err
+	}
+
+	pool := config.DB.POOL
+	if pool.MAX_CONNS > 0 {
+		poolConfig.MaxConns = pool.MAX_CONNS
+	}
+	if pool.MIN_CONNS > 0 {
+		poolConfig.MinConns = pool.MIN_CONNS
+	}
+	if pool.MAX_CONN_LIFETIME > 0 {
+		poolConfig.MaxConnLifetime = pool.MAX_CONN_LIFETIME
+	}
+	if pool.MAX_CONN_IDLE_TIME > 0 {
+		poolConfig.MaxConnIdleTime = pool.MAX_CONN_IDLE_TIME
+	}
+	if pool.HEALTH_CHECK_PERIOD > 0 {
+		poolConfig.HealthCheckPeriod = pool.HEALTH_CHECK_PERIOD
+	}
+
+	return poolConfig, nil
+}
+
+// NewPostgresqlDB returns a connection pool which is safe for concurrent use
+// and can be handed to db.New as its DBTX
+func NewPostgresqlDB(lc fx.Lifecycle, config *config.Config) *pgxpool.Pool {
+	poolConfig, err := NewPostgresqlPoolConfig(config)
+	if err != nil {
+		panic(err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		panic(err)
 	}
 
 	lc.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error { return conn.Close(ctx) },
+		OnStop: func(ctx context.Context) error {
+			pool.Close()
+			return nil
+		},
 	})
 
-	return conn
+	return pool
+}
+
+// GetSqliteDSN returns the dsn of the sqlite database, DB_NAME=:memory: gives
+// an in-memory database. LIKE is case sensitive like on postgresql.
+func GetSqliteDSN(config *config.Config) string {
+	name := config.DB.NAME
+	if IsSqliteInMemory(config) {
+		name = "file::memory:"
+	}
+
+	sep := "?"
+	if strings.Contains(name, "?") {
+		sep = "&"
+	}
+
+	return name + sep + "_foreign_keys=on&_busy_timeout=5000&_case_sensitive_like=on"
 }
 
-func NewSqliteDB(config *config.Config) *sql.DB {
+// IsSqliteInMemory reports whether the sqlite database lives in memory only
+func IsSqliteInMemory(config *config.Config) bool {
 	name := config.DB.NAME
-	db, err := sql.Open("sqlite3", name)
+	return name == ":memory:" || strings.Contains(name, "mode=memory")
+}
+
+func NewSqliteDB(lc fx.Lifecycle, config *config.Config) *sql.DB {
+	db, err := sql.Open("sqlite3", GetSqliteDSN(config))
 	if err != nil {
 		panic(err)
 	}
 
+	if IsSqliteInMemory(config) {
+		// every new connection opens a brand new in-memory database, so stick
+		// to a single one which is never recycled
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxLifetime(0)
+		db.SetConnMaxIdleTime(0)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error { return db.Close() },
+	})
+
 	return db
 }
@@ -0,0 +1,11 @@
+package db
+
+import "exampleproj/internal/health"
+
+// NewHealthCheck fails the readiness while the database is unreachable
+func NewHealthCheck(store Store) health.Check {
+	return health.Check{
+		Name:  "database",
+		Probe: store.Ping,
+	}
+}
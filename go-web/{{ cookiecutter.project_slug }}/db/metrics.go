@@ -0,0 +1,75 @@
+package db
+
+import (
+	"exampleproj/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports the PoolStats of the store on every scrape
+type PoolCollector struct {
+	store Store
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquires          *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+	newConns          *prometheus.Desc
+	destroyedConns    *prometheus.Desc
+}
+
+func NewPoolCollector(store Store) *PoolCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "db_pool", name), help, labels, nil)
+	}
+
+	return &PoolCollector{
+		store:             store,
+		acquiredConns:     desc("acquired_conns", "The connections in use."),
+		idleConns:         desc("idle_conns", "The idle connections."),
+		constructingConns: desc("constructing_conns", "The connections being opened."),
+		totalConns:        desc("total_conns", "The open connections."),
+		maxConns:          desc("max_conns", "The size of the pool."),
+		acquires:          desc("acquires_total", "The connections acquired."),
+		acquireDuration:   desc("acquire_duration_seconds_total", "The time spent acquiring the connections."),
+		emptyAcquires:     desc("empty_acquires_total", "The acquires which waited for a connection, the pool was empty."),
+		canceledAcquires:  desc("canceled_acquires_total", "The acquires canceled by their context."),
+		newConns:          desc("new_conns_total", "The connections opened."),
+		destroyedConns:    desc("destroyed_conns_total", "The connections closed by the pool.", "reason"),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.store.Stats()
+
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+	counter := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+	}
+
+	gauge(c.acquiredConns, float64(stats.AcquiredConns))
+	gauge(c.idleConns, float64(stats.IdleConns))
+	gauge(c.constructingConns, float64(stats.ConstructingConns))
+	gauge(c.totalConns, float64(stats.TotalConns))
+	gauge(c.maxConns, float64(stats.MaxConns))
+	counter(c.acquires, float64(stats.AcquireCount))
+	counter(c.acquireDuration, stats.AcquireDuration.Seconds())
+	counter(c.emptyAcquires, float64(stats.EmptyAcquireCount))
+	counter(c.canceledAcquires, float64(stats.CanceledAcquireCount))
+	counter(c.newConns, float64(stats.NewConnsCount))
+	counter(c.destroyedConns, float64(stats.MaxLifetimeDestroyCount), "max_lifetime")
+	counter(c.destroyedConns, float64(stats.MaxIdleDestroyCount), "max_idle")
+}
+
+var _ prometheus.Collector = (*PoolCollector)(nil)
@@ -0,0 +1,9 @@
+-- Modify "users" table, the columns are nullable until the existing rows are backfilled
+ALTER TABLE "users" ADD COLUMN "email" text NULL, ADD COLUMN "password_hash" text NULL;
+-- Backfill the existing users with a unique placeholder email and an empty hash, no
+-- password matches it so they can't log in until their credentials are set
+UPDATE "users" SET "email" = 'user-' || "id" || '@users.invalid', "password_hash" = '' WHERE "email" IS NULL;
+-- Modify "users" table
+ALTER TABLE "users" ALTER COLUMN "email" SET NOT NULL, ALTER COLUMN "password_hash" SET NOT NULL;
+-- Create index "users_email_key" to table: "users"
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email");
@@ -0,0 +1,37 @@
+-- Create "roles" table
+CREATE TABLE "roles" (
+ "id" serial NOT NULL,
+ "name" text NOT NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "roles_name_key" to table: "roles"
+CREATE UNIQUE INDEX "roles_name_key" ON "roles" ("name");
+-- Create "permissions" table
+CREATE TABLE "permissions" (
+ "id" serial NOT NULL,
+ "name" text NOT NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "permissions_name_key" to table: "permissions"
+CREATE UNIQUE INDEX "permissions_name_key" ON "permissions" ("name");
+-- Create "role_permissions" table
+CREATE TABLE "role_permissions" (
+ "role_id" integer NOT NULL,
+ "permission_id" integer NOT NULL,
+ PRIMARY KEY ("role_id", "permission_id"),
+ CONSTRAINT "role_permissions_permission_id_fkey" FOREIGN KEY ("permission_id") REFERENCES "permissions" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+ CONSTRAINT "role_permissions_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create "user_roles" table
+CREATE TABLE "user_roles" (
+ "user_id" integer NOT NULL,
+ "role_id" integer NOT NULL,
+ PRIMARY KEY ("user_id", "role_id"),
+ CONSTRAINT "user_roles_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+ CONSTRAINT "user_roles_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Seed the "admin" role with every permission
+INSERT INTO "roles" ("name") VALUES ('admin');
+INSERT INTO "permissions" ("name") VALUES ('users:update'), ('users:delete');
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id" FROM "roles", "permissions" WHERE "roles"."name" = 'admin';
@@ -0,0 +1,28 @@
+-- Modify "authors" table, "user_id" is nullable until the existing rows are backfilled
+ALTER TABLE "authors" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY, ADD COLUMN "user_id" integer NULL, ADD CONSTRAINT "authors_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Start the identity past the existing ids
+SELECT setval(pg_get_serial_sequence('authors', 'id'), COALESCE(MAX("id"), 0) + 1, false) FROM "authors";
+-- The existing authors need an owner, create a placeholder one when there's no user,
+-- its empty hash matches no password
+INSERT INTO "users" ("name", "email", "password_hash")
+SELECT 'legacy authors', 'legacy-authors@users.invalid', ''
+WHERE EXISTS (SELECT 1 FROM "authors") AND NOT EXISTS (SELECT 1 FROM "users");
+-- Backfill the owner of the existing authors, the first admin or else the first user
+UPDATE "authors" SET "user_id" = (
+  SELECT "users"."id" FROM "users"
+  LEFT JOIN "user_roles" ON "user_roles"."user_id" = "users"."id"
+  LEFT JOIN "roles" ON "roles"."id" = "user_roles"."role_id" AND "roles"."name" = 'admin'
+  ORDER BY "roles"."id" IS NULL, "users"."id"
+  LIMIT 1
+) WHERE "user_id" IS NULL;
+-- Modify "authors" table
+ALTER TABLE "authors" ALTER COLUMN "user_id" SET NOT NULL;
+-- Create index "authors_user_id_idx" to table: "authors"
+CREATE INDEX "authors_user_id_idx" ON "authors" ("user_id");
+-- Create index "authors_name_id_idx" to table: "authors"
+CREATE INDEX "authors_name_id_idx" ON "authors" ("name", "id");
+-- Grant the author permissions to the "admin" role
+INSERT INTO "permissions" ("name") VALUES ('authors:update'), ('authors:delete');
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id" FROM "roles", "permissions"
+WHERE "roles"."name" = 'admin' AND "permissions"."name" IN ('authors:update', 'authors:delete');
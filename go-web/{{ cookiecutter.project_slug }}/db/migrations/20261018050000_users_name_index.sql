@@ -0,0 +1,2 @@
+-- Create index "users_name_id_idx" to table: "users"
+CREATE INDEX "users_name_id_idx" ON "users" ("name", "id");
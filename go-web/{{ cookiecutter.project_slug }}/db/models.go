@@ -9,12 +9,35 @@ import (
 )
 
 type Author struct {
+	ID     int32
+	Name   string
+	Bio    pgtype.Text
+	UserID int32
+}
+
+type Permission struct {
 	ID   int32
 	Name string
-	Bio  pgtype.Text
 }
 
-type User struct {
+type Role struct {
 	ID   int32
 	Name string
 }
+
+type RolePermission struct {
+	RoleID       int32
+	PermissionID int32
+}
+
+type User struct {
+	ID           int32
+	Name         string
+	Email        string
+	PasswordHash string
+}
+
+type UserRole struct {
+	UserID int32
+	RoleID int32
+}
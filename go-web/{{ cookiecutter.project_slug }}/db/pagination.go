@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"exampleproj/internal/pagination"
+)
+
+// keyset are the args of the List*By* queries, the After ones seek past the
+// name and id of the cursor
+type keyset struct {
+	prefix string
+	name   string
+	id     int32
+	limit  int64
+}
+
+// keysetQuery runs one of the List*By* queries of a table
+type keysetQuery[T any] func(ctx context.Context, args keyset) ([]T, error)
+
+// keysetQueries are the List*By* queries of a table by sort, the first page
+// and the pages after the cursor
+type keysetQueries[T any] struct {
+	byName, byNameAfter         keysetQuery[T]
+	byNameDesc, byNameDescAfter keysetQuery[T]
+	byID, byIDAfter             keysetQuery[T]
+	byIDDesc, byIDDescAfter     keysetQuery[T]
+
+	// key returns the name and the id of a row
+	key func(T) (string, int32)
+}
+
+// listPage runs the keyset query of the sort of params, the sorts and
+// filters are the ones of a pagination.Spec with the name and id sorts and
+// the name filter
+func listPage[T any](ctx context.Context, queries keysetQueries[T], params pagination.Params) (pagination.Page[T], error) {
+	var first, after keysetQuery[T]
+	switch params.Sort {
+	case pagination.Sort{Field: "name"}:
+		first, after = queries.byName, queries.byNameAfter
+	case pagination.Sort{Field: "name", Desc: true}:
+		first, after = queries.byNameDesc, queries.byNameDescAfter
+	case pagination.Sort{Field: "id"}:
+		first, after = queries.byID, queries.byIDAfter
+	default:
+		first, after = queries.byIDDesc, queries.byIDDescAfter
+	}
+
+	query := first
+	if params.After != nil {
+		query = after
+	}
+	rows, err := query(ctx, keyset{
+		prefix: likePrefix(params.Filters["name"]),
+		name:   params.AfterValue(),
+		id:     params.AfterID(),
+		limit:  params.FetchLimit(),
+	})
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	return pagination.NewPage(rows, params, func(row T) (string, int32) {
+		name, id := queries.key(row)
+		if params.Sort.Field == "id" {
+			return "", id
+		}
+		return name, id
+	}), nil
+}
+
+// likeEscaper escapes the wildcards of LIKE, the queries declare the
+// backslash as ESCAPE character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix is the name_prefix of the List*By* queries matching the names
+// which start with prefix
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(prefix)
+}
+
+// UsersPagination are the sorts and filters ListUsersPage supports
+var UsersPagination = pagination.Spec{
+	Sorts:   []string{"name", "id"},
+	Filters: []string{"name"},
+}
+
+// ListUsersPage runs the keyset query of the sort of params
+func ListUsersPage(ctx context.Context, q Querier, params pagination.Params) (pagination.Page[User], error) {
+	return listPage(ctx, keysetQueries[User]{
+		byName: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByName(ctx, ListUsersByNameParams{k.prefix, k.limit})
+		},
+		byNameAfter: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByNameAfter(ctx, ListUsersByNameAfterParams{k.prefix, k.name, k.id, k.limit})
+		},
+		byNameDesc: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByNameDesc(ctx, ListUsersByNameDescParams{k.prefix, k.limit})
+		},
+		byNameDescAfter: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByNameDescAfter(ctx, ListUsersByNameDescAfterParams{k.prefix, k.name, k.id, k.limit})
+		},
+		byID: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByID(ctx, ListUsersByIDParams{k.prefix, k.limit})
+		},
+		byIDAfter: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByIDAfter(ctx, ListUsersByIDAfterParams{k.prefix, k.id, k.limit})
+		},
+		byIDDesc: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByIDDesc(ctx, ListUsersByIDDescParams{k.prefix, k.limit})
+		},
+		byIDDescAfter: func(ctx context.Context, k keyset) ([]User, error) {
+			return q.ListUsersByIDDescAfter(ctx, ListUsersByIDDescAfterParams{k.prefix, k.id, k.limit})
+		},
+		key: func(user User) (string, int32) {
+			return user.Name, user.ID
+		},
+	}, params)
+}
+
+// AuthorsPagination are the sorts and filters ListAuthorsPage supports
+var AuthorsPagination = pagination.Spec{
+	Sorts:   []string{"name", "id"},
+	Filters: []string{"name"},
+}
+
+// ListAuthorsPage runs the keyset query of the sort of params
+func ListAuthorsPage(ctx context.Context, q Querier, params pagination.Params) (pagination.Page[Author], error) {
+	return listPage(ctx, keysetQueries[Author]{
+		byName: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByName(ctx, ListAuthorsByNameParams{k.prefix, k.limit})
+		},
+		byNameAfter: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByNameAfter(ctx, ListAuthorsByNameAfterParams{k.prefix, k.name, k.id, k.limit})
+		},
+		byNameDesc: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByNameDesc(ctx, ListAuthorsByNameDescParams{k.prefix, k.limit})
+		},
+		byNameDescAfter: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByNameDescAfter(ctx, ListAuthorsByNameDescAfterParams{k.prefix, k.name, k.id, k.limit})
+		},
+		byID: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByID(ctx, ListAuthorsByIDParams{k.prefix, k.limit})
+		},
+		byIDAfter: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByIDAfter(ctx, ListAuthorsByIDAfterParams{k.prefix, k.id, k.limit})
+		},
+		byIDDesc: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByIDDesc(ctx, ListAuthorsByIDDescParams{k.prefix, k.limit})
+		},
+		byIDDescAfter: func(ctx context.Context, k keyset) ([]Author, error) {
+			return q.ListAuthorsByIDDescAfter(ctx, ListAuthorsByIDDescAfterParams{k.prefix, k.id, k.limit})
+		},
+		key: func(author Author) (string, int32) {
+			return author.Name, author.ID
+		},
+	}, params)
+}
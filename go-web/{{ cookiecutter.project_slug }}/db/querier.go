@@ -0,0 +1,50 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+
+package db
+
+import (
+	"context"
+)
+
+type Querier interface {
+	AssignRole(ctx context.Context, arg AssignRoleParams) error
+	CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error)
+	CreatePermission(ctx context.Context, name string) (Permission, error)
+	CreateRole(ctx context.Context, name string) (Role, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAuthor(ctx context.Context, id int32) error
+	DeleteUser(ctx context.Context, id int32) error
+	GetAuthor(ctx context.Context, id int32) (Author, error)
+	GetPermissionByName(ctx context.Context, name string) (Permission, error)
+	GetRoleByName(ctx context.Context, name string) (Role, error)
+	GetUser(ctx context.Context, id int32) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GrantPermission(ctx context.Context, arg GrantPermissionParams) error
+	ListAuthorsByID(ctx context.Context, arg ListAuthorsByIDParams) ([]Author, error)
+	ListAuthorsByIDAfter(ctx context.Context, arg ListAuthorsByIDAfterParams) ([]Author, error)
+	ListAuthorsByIDDesc(ctx context.Context, arg ListAuthorsByIDDescParams) ([]Author, error)
+	ListAuthorsByIDDescAfter(ctx context.Context, arg ListAuthorsByIDDescAfterParams) ([]Author, error)
+	// the List*By* queries are the first keyset pages of internal/pagination,
+	// the List*By*After ones the pages after the cursor
+	ListAuthorsByName(ctx context.Context, arg ListAuthorsByNameParams) ([]Author, error)
+	ListAuthorsByNameAfter(ctx context.Context, arg ListAuthorsByNameAfterParams) ([]Author, error)
+	ListAuthorsByNameDesc(ctx context.Context, arg ListAuthorsByNameDescParams) ([]Author, error)
+	ListAuthorsByNameDescAfter(ctx context.Context, arg ListAuthorsByNameDescAfterParams) ([]Author, error)
+	ListUserPermissions(ctx context.Context, userID int32) ([]string, error)
+	ListUsersByID(ctx context.Context, arg ListUsersByIDParams) ([]User, error)
+	ListUsersByIDAfter(ctx context.Context, arg ListUsersByIDAfterParams) ([]User, error)
+	ListUsersByIDDesc(ctx context.Context, arg ListUsersByIDDescParams) ([]User, error)
+	ListUsersByIDDescAfter(ctx context.Context, arg ListUsersByIDDescAfterParams) ([]User, error)
+	// the List*By* queries are the first keyset pages of internal/pagination,
+	// the List*By*After ones the pages after the cursor
+	ListUsersByName(ctx context.Context, arg ListUsersByNameParams) ([]User, error)
+	ListUsersByNameAfter(ctx context.Context, arg ListUsersByNameAfterParams) ([]User, error)
+	ListUsersByNameDesc(ctx context.Context, arg ListUsersByNameDescParams) ([]User, error)
+	ListUsersByNameDescAfter(ctx context.Context, arg ListUsersByNameDescAfterParams) ([]User, error)
+	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+}
+
+var _ Querier = (*Queries)(nil)
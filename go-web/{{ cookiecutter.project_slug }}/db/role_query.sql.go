@@ -0,0 +1,132 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: role_query.sql
+
+package db
+
+import (
+	"context"
+)
+
+const assignRole = `-- name: AssignRole :exec
+INSERT INTO user_roles (
+  user_id, role_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type AssignRoleParams struct {
+	UserID int32
+	RoleID int32
+}
+
+func (q *Queries) AssignRole(ctx context.Context, arg AssignRoleParams) error {
+	_, err := q.db.Exec(ctx, assignRole, arg.UserID, arg.RoleID)
+	return err
+}
+
+const createPermission = `-- name: CreatePermission :one
+INSERT INTO permissions (
+  name
+) VALUES (
+  $1
+)
+RETURNING id, name
+`
+
+func (q *Queries) CreatePermission(ctx context.Context, name string) (Permission, error) {
+	row := q.db.QueryRow(ctx, createPermission, name)
+	var i Permission
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const createRole = `-- name: CreateRole :one
+INSERT INTO roles (
+  name
+) VALUES (
+  $1
+)
+RETURNING id, name
+`
+
+func (q *Queries) CreateRole(ctx context.Context, name string) (Role, error) {
+	row := q.db.QueryRow(ctx, createRole, name)
+	var i Role
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const getPermissionByName = `-- name: GetPermissionByName :one
+SELECT id, name FROM permissions
+WHERE name = $1 LIMIT 1
+`
+
+func (q *Queries) GetPermissionByName(ctx context.Context, name string) (Permission, error) {
+	row := q.db.QueryRow(ctx, getPermissionByName, name)
+	var i Permission
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const getRoleByName = `-- name: GetRoleByName :one
+SELECT id, name FROM roles
+WHERE name = $1 LIMIT 1
+`
+
+func (q *Queries) GetRoleByName(ctx context.Context, name string) (Role, error) {
+	row := q.db.QueryRow(ctx, getRoleByName, name)
+	var i Role
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const grantPermission = `-- name: GrantPermission :exec
+INSERT INTO role_permissions (
+  role_id, permission_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type GrantPermissionParams struct {
+	RoleID       int32
+	PermissionID int32
+}
+
+func (q *Queries) GrantPermission(ctx context.Context, arg GrantPermissionParams) error {
+	_, err := q.db.Exec(ctx, grantPermission, arg.RoleID, arg.PermissionID)
+	return err
+}
+
+const listUserPermissions = `-- name: ListUserPermissions :many
+SELECT DISTINCT permissions.name FROM permissions
+JOIN role_permissions ON role_permissions.permission_id = permissions.id
+JOIN user_roles ON user_roles.role_id = role_permissions.role_id
+WHERE user_roles.user_id = $1
+ORDER BY permissions.name
+`
+
+func (q *Queries) ListUserPermissions(ctx context.Context, userID int32) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUserPermissions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -1,5 +1,10 @@
 CREATE TABLE authors (
-  id   INTEGER PRIMARY KEY,
-  name text    NOT NULL,
-  bio  text
-);
\ No newline at end of file
+  id      integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  name    text    NOT NULL,
+  bio     text,
+  -- the user who created the author, it owns it
+  user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX authors_user_id_idx ON authors (user_id);
+CREATE INDEX authors_name_id_idx ON authors (name, id);
@@ -0,0 +1,22 @@
+CREATE TABLE roles (
+  id   SERIAL PRIMARY KEY,
+  name text    NOT NULL UNIQUE
+);
+
+-- permissions are named after the resource and the action, ex. users:delete
+CREATE TABLE permissions (
+  id   SERIAL PRIMARY KEY,
+  name text    NOT NULL UNIQUE
+);
+
+CREATE TABLE role_permissions (
+  role_id       integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+  permission_id integer NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+  PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE user_roles (
+  user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role_id integer NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+  PRIMARY KEY (user_id, role_id)
+);
\ No newline at end of file
@@ -1,4 +1,8 @@
 CREATE TABLE users (
-  id   SERIAL PRIMARY KEY,
-  name text    NOT NULL
-);
\ No newline at end of file
+  id            SERIAL PRIMARY KEY,
+  name          text    NOT NULL,
+  email         text    NOT NULL UNIQUE,
+  password_hash text    NOT NULL
+);
+
+CREATE INDEX users_name_id_idx ON users (name, id);
@@ -2,19 +2,69 @@
 SELECT * FROM authors
 WHERE id = $1 LIMIT 1;
 
--- name: ListAuthors :many
+-- the List*By* queries are the first keyset pages of internal/pagination,
+-- the List*By*After ones the pages after the cursor
+-- name: ListAuthorsByName :many
 SELECT * FROM authors
-ORDER BY name;
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByNameAfter :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND (name, id) > (sqlc.arg(after_name)::text, sqlc.arg(after_id)::int)
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByNameDesc :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByNameDescAfter :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND (name, id) < (sqlc.arg(before_name)::text, sqlc.arg(before_id)::int)
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByID :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByIDAfter :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND id > sqlc.arg(after_id)::int
+ORDER BY id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByIDDesc :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListAuthorsByIDDescAfter :many
+SELECT * FROM authors
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND id < sqlc.arg(before_id)::int
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
 
 -- name: CreateAuthor :one
 INSERT INTO authors (
-  name, bio
+  name, bio, user_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
 RETURNING *;
 
--- name: UpdateAuthor :exec
+-- name: UpdateAuthor :one
 UPDATE authors
 set name = $1,
 bio = $2
@@ -3,19 +3,73 @@
 SELECT * FROM users
 WHERE id = $1 LIMIT 1;
 
--- name: ListUsers :many
+-- the List*By* queries are the first keyset pages of internal/pagination,
+-- the List*By*After ones the pages after the cursor
+-- name: ListUsersByName :many
 SELECT * FROM users
-ORDER BY name;
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByNameAfter :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND (name, id) > (sqlc.arg(after_name)::text, sqlc.arg(after_id)::int)
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByNameDesc :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByNameDescAfter :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND (name, id) < (sqlc.arg(before_name)::text, sqlc.arg(before_id)::int)
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByID :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByIDAfter :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND id > sqlc.arg(after_id)::int
+ORDER BY id
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByIDDesc :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: ListUsersByIDDescAfter :many
+SELECT * FROM users
+WHERE name LIKE sqlc.arg(name_prefix)::text || '%' ESCAPE '\'
+  AND id < sqlc.arg(before_id)::int
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)::bigint;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
 
 -- name: CreateUser :one
 INSERT INTO users (
-  name
+  name, email, password_hash
 ) VALUES (
-  $1
+  $1, $2, $3
 )
 RETURNING *;
 
--- name: UpdateUser :exec
+-- name: UpdateUser :one
 UPDATE users
 set name = $1
 WHERE id = $2
@@ -0,0 +1,434 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: author_query.sql
+
+package sqlite
+
+import (
+	"context"
+
+	pgxtype "github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuthor = `-- name: CreateAuthor :one
+INSERT INTO authors (
+  name, bio, user_id
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, name, bio, user_id
+`
+
+type CreateAuthorParams struct {
+	Name   string
+	Bio    pgxtype.Text
+	UserID int32
+}
+
+func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error) {
+	row := q.db.QueryRowContext(ctx, createAuthor, arg.Name, arg.Bio, arg.UserID)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const deleteAuthor = `-- name: DeleteAuthor :exec
+DELETE FROM authors
+WHERE id = ?
+`
+
+func (q *Queries) DeleteAuthor(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteAuthor, id)
+	return err
+}
+
+const getAuthor = `-- name: GetAuthor :one
+SELECT id, name, bio, user_id FROM authors
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetAuthor(ctx context.Context, id int32) (Author, error) {
+	row := q.db.QueryRowContext(ctx, getAuthor, id)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const listAuthorsByID = `-- name: ListAuthorsByID :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY id
+LIMIT ?2
+`
+
+type ListAuthorsByIDParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByID(ctx context.Context, arg ListAuthorsByIDParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByID, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDAfter = `-- name: ListAuthorsByIDAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND id > CAST(?2 AS INTEGER)
+ORDER BY id
+LIMIT ?3
+`
+
+type ListAuthorsByIDAfterParams struct {
+	NamePrefix string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDAfter(ctx context.Context, arg ListAuthorsByIDAfterParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByIDAfter, arg.NamePrefix, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDDesc = `-- name: ListAuthorsByIDDesc :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT ?2
+`
+
+type ListAuthorsByIDDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDDesc(ctx context.Context, arg ListAuthorsByIDDescParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByIDDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByIDDescAfter = `-- name: ListAuthorsByIDDescAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND id < CAST(?2 AS INTEGER)
+ORDER BY id DESC
+LIMIT ?3
+`
+
+type ListAuthorsByIDDescAfterParams struct {
+	NamePrefix string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByIDDescAfter(ctx context.Context, arg ListAuthorsByIDDescAfterParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByIDDescAfter, arg.NamePrefix, arg.BeforeID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByName = `-- name: ListAuthorsByName :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT ?2
+`
+
+type ListAuthorsByNameParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+// the List*By* queries are the first keyset pages of internal/pagination,
+// the List*By*After ones the pages after the cursor
+func (q *Queries) ListAuthorsByName(ctx context.Context, arg ListAuthorsByNameParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByName, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByNameAfter = `-- name: ListAuthorsByNameAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) > (CAST(?2 AS TEXT), CAST(?3 AS INTEGER))
+ORDER BY name, id
+LIMIT ?4
+`
+
+type ListAuthorsByNameAfterParams struct {
+	NamePrefix string
+	AfterName  string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameAfter(ctx context.Context, arg ListAuthorsByNameAfterParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByNameAfter,
+		arg.NamePrefix,
+		arg.AfterName,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByNameDesc = `-- name: ListAuthorsByNameDesc :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT ?2
+`
+
+type ListAuthorsByNameDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameDesc(ctx context.Context, arg ListAuthorsByNameDescParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByNameDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByNameDescAfter = `-- name: ListAuthorsByNameDescAfter :many
+SELECT id, name, bio, user_id FROM authors
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) < (CAST(?2 AS TEXT), CAST(?3 AS INTEGER))
+ORDER BY name DESC, id DESC
+LIMIT ?4
+`
+
+type ListAuthorsByNameDescAfterParams struct {
+	NamePrefix string
+	BeforeName string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListAuthorsByNameDescAfter(ctx context.Context, arg ListAuthorsByNameDescAfterParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByNameDescAfter,
+		arg.NamePrefix,
+		arg.BeforeName,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAuthor = `-- name: UpdateAuthor :one
+UPDATE authors
+set name = ?,
+bio = ?
+WHERE id = ?
+RETURNING id, name, bio, user_id
+`
+
+type UpdateAuthorParams struct {
+	Name string
+	Bio  pgxtype.Text
+	ID   int32
+}
+
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error) {
+	row := q.db.QueryRowContext(ctx, updateAuthor, arg.Name, arg.Bio, arg.ID)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.UserID,
+	)
+	return i, err
+}
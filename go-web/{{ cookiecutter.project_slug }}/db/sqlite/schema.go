@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"sort"
+)
+
+//go:embed schemas/*.sql
+var schemaFS embed.FS
+
+// ApplySchema creates the tables described in schemas/ when they are missing.
+//
+// There is no migration history for sqlite, it's meant for local work and
+// tests, so drop the database file after changing the schema.
+func ApplySchema(ctx context.Context, db *sql.DB) error {
+	files, err := fs.Glob(schemaFS, "schemas/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		stmt, err := schemaFS.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, string(stmt)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS authors (
+  id      INTEGER PRIMARY KEY AUTOINCREMENT,
+  name    text    NOT NULL,
+  bio     text,
+  -- the user who created the author, it owns it
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS authors_user_id_idx ON authors (user_id);
+CREATE INDEX IF NOT EXISTS authors_name_id_idx ON authors (name, id);
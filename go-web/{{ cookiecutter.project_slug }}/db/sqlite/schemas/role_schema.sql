@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS roles (
+  id   INTEGER PRIMARY KEY AUTOINCREMENT,
+  name text    NOT NULL UNIQUE
+);
+
+-- permissions are named after the resource and the action, ex. users:delete
+CREATE TABLE IF NOT EXISTS permissions (
+  id   INTEGER PRIMARY KEY AUTOINCREMENT,
+  name text    NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role_id       INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+  permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+  PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role_id INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+  PRIMARY KEY (user_id, role_id)
+);
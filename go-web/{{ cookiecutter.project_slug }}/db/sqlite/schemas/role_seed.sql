@@ -0,0 +1,5 @@
+-- keep in sync with the seed of the postgresql migration
+INSERT OR IGNORE INTO roles (name) VALUES ('admin');
+INSERT OR IGNORE INTO permissions (name) VALUES ('users:update'), ('users:delete'), ('authors:update'), ('authors:delete');
+INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions WHERE roles.name = 'admin';
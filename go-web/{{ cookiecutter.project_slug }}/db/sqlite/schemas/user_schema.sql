@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS users (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT,
+  name          text    NOT NULL,
+  email         text    NOT NULL UNIQUE,
+  password_hash text    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS users_name_id_idx ON users (name, id);
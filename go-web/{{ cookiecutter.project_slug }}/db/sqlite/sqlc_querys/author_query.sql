@@ -0,0 +1,76 @@
+-- name: GetAuthor :one
+SELECT * FROM authors
+WHERE id = ? LIMIT 1;
+
+-- the List*By* queries are the first keyset pages of internal/pagination,
+-- the List*By*After ones the pages after the cursor
+-- name: ListAuthorsByName :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByNameAfter :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) > (CAST(sqlc.arg(after_name) AS TEXT), CAST(sqlc.arg(after_id) AS INTEGER))
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByNameDesc :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByNameDescAfter :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) < (CAST(sqlc.arg(before_name) AS TEXT), CAST(sqlc.arg(before_id) AS INTEGER))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByID :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByIDAfter :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND id > CAST(sqlc.arg(after_id) AS INTEGER)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByIDDesc :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuthorsByIDDescAfter :many
+SELECT * FROM authors
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND id < CAST(sqlc.arg(before_id) AS INTEGER)
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateAuthor :one
+INSERT INTO authors (
+  name, bio, user_id
+) VALUES (
+  ?, ?, ?
+)
+RETURNING *;
+
+-- name: UpdateAuthor :one
+UPDATE authors
+set name = ?,
+bio = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteAuthor :exec
+DELETE FROM authors
+WHERE id = ?;
\ No newline at end of file
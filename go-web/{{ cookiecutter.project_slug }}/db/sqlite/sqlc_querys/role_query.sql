@@ -0,0 +1,46 @@
+-- name: GetRoleByName :one
+SELECT * FROM roles
+WHERE name = ? LIMIT 1;
+
+-- name: CreateRole :one
+INSERT INTO roles (
+  name
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: GetPermissionByName :one
+SELECT * FROM permissions
+WHERE name = ? LIMIT 1;
+
+-- name: CreatePermission :one
+INSERT INTO permissions (
+  name
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: GrantPermission :exec
+INSERT INTO role_permissions (
+  role_id, permission_id
+) VALUES (
+  ?, ?
+)
+ON CONFLICT DO NOTHING;
+
+-- name: AssignRole :exec
+INSERT INTO user_roles (
+  user_id, role_id
+) VALUES (
+  ?, ?
+)
+ON CONFLICT DO NOTHING;
+
+-- name: ListUserPermissions :many
+SELECT DISTINCT permissions.name FROM permissions
+JOIN role_permissions ON role_permissions.permission_id = permissions.id
+JOIN user_roles ON user_roles.role_id = role_permissions.role_id
+WHERE user_roles.user_id = ?
+ORDER BY permissions.name;
\ No newline at end of file
@@ -0,0 +1,80 @@
+
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = ? LIMIT 1;
+
+-- the List*By* queries are the first keyset pages of internal/pagination,
+-- the List*By*After ones the pages after the cursor
+-- name: ListUsersByName :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByNameAfter :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) > (CAST(sqlc.arg(after_name) AS TEXT), CAST(sqlc.arg(after_id) AS INTEGER))
+ORDER BY name, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByNameDesc :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByNameDescAfter :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) < (CAST(sqlc.arg(before_name) AS TEXT), CAST(sqlc.arg(before_id) AS INTEGER))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByID :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByIDAfter :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND id > CAST(sqlc.arg(after_id) AS INTEGER)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByIDDesc :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUsersByIDDescAfter :many
+SELECT * FROM users
+WHERE name LIKE CAST(sqlc.arg(name_prefix) AS TEXT) || '%' ESCAPE '\'
+  AND id < CAST(sqlc.arg(before_id) AS INTEGER)
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = ? LIMIT 1;
+
+-- name: CreateUser :one
+INSERT INTO users (
+  name, email, password_hash
+) VALUES (
+  ?, ?, ?
+)
+RETURNING *;
+
+-- name: UpdateUser :one
+UPDATE users
+set name = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
\ No newline at end of file
@@ -0,0 +1,447 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: user_query.sql
+
+package sqlite
+
+import (
+	"context"
+)
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (
+  name, email, password_hash
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, name, email, password_hash
+`
+
+type CreateUserParams struct {
+	Name         string
+	Email        string
+	PasswordHash string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.Name, arg.Email, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, name, email, password_hash FROM users
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, password_hash FROM users
+WHERE email = ? LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const listUsersByID = `-- name: ListUsersByID :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY id
+LIMIT ?2
+`
+
+type ListUsersByIDParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByID(ctx context.Context, arg ListUsersByIDParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByID, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDAfter = `-- name: ListUsersByIDAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND id > CAST(?2 AS INTEGER)
+ORDER BY id
+LIMIT ?3
+`
+
+type ListUsersByIDAfterParams struct {
+	NamePrefix string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDAfter(ctx context.Context, arg ListUsersByIDAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByIDAfter, arg.NamePrefix, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDDesc = `-- name: ListUsersByIDDesc :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT ?2
+`
+
+type ListUsersByIDDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDDesc(ctx context.Context, arg ListUsersByIDDescParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByIDDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDDescAfter = `-- name: ListUsersByIDDescAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND id < CAST(?2 AS INTEGER)
+ORDER BY id DESC
+LIMIT ?3
+`
+
+type ListUsersByIDDescAfterParams struct {
+	NamePrefix string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDDescAfter(ctx context.Context, arg ListUsersByIDDescAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByIDDescAfter, arg.NamePrefix, arg.BeforeID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByName = `-- name: ListUsersByName :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT ?2
+`
+
+type ListUsersByNameParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+// the List*By* queries are the first keyset pages of internal/pagination,
+// the List*By*After ones the pages after the cursor
+func (q *Queries) ListUsersByName(ctx context.Context, arg ListUsersByNameParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByName, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameAfter = `-- name: ListUsersByNameAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) > (CAST(?2 AS TEXT), CAST(?3 AS INTEGER))
+ORDER BY name, id
+LIMIT ?4
+`
+
+type ListUsersByNameAfterParams struct {
+	NamePrefix string
+	AfterName  string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameAfter(ctx context.Context, arg ListUsersByNameAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByNameAfter,
+		arg.NamePrefix,
+		arg.AfterName,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameDesc = `-- name: ListUsersByNameDesc :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT ?2
+`
+
+type ListUsersByNameDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameDesc(ctx context.Context, arg ListUsersByNameDescParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByNameDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameDescAfter = `-- name: ListUsersByNameDescAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE CAST(?1 AS TEXT) || '%' ESCAPE '\'
+  AND (name, id) < (CAST(?2 AS TEXT), CAST(?3 AS INTEGER))
+ORDER BY name DESC, id DESC
+LIMIT ?4
+`
+
+type ListUsersByNameDescAfterParams struct {
+	NamePrefix string
+	BeforeName string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameDescAfter(ctx context.Context, arg ListUsersByNameDescAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByNameDescAfter,
+		arg.NamePrefix,
+		arg.BeforeName,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+set name = ?
+WHERE id = ?
+RETURNING id, name, email, password_hash
+`
+
+type UpdateUserParams struct {
+	Name string
+	ID   int32
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser, arg.Name, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
+}
@@ -0,0 +1,312 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"exampleproj/config"
+	"exampleproj/db/sqlite"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/fx"
+)
+
+// Store is the engine agnostic entry point of the database, handlers depend
+// on it instead of a concrete driver so they run against postgres and sqlite
+// unchanged.
+type Store interface {
+	Querier
+
+	// Ping checks the database is reachable
+	Ping(ctx context.Context) error
+
+	// Stats returns a snapshot of the connection pool
+	Stats() PoolStats
+
+	// ExecTx runs fn with a Querier bound to a new transaction, which is
+	// committed when fn returns nil and rolled back otherwise
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+}
+
+// Module provides the Store selected by config.DB.ENGINE
+var Module = fx.Module("db",
+	fx.Provide(NewStore),
+)
+
+// NewStore opens the database configured by DB.ENGINE and wraps it in a Store
+func NewStore(lc fx.Lifecycle, cfg *config.Config) (Store, error) {
+	switch cfg.DB.ENGINE {
+	case config.Postgres:
+		return NewPostgresqlStore(NewPostgresqlDB(lc, cfg)), nil
+	case config.SQLite:
+		conn := NewSqliteDB(lc, cfg)
+		if err := sqlite.ApplySchema(context.Background(), conn); err != nil {
+			return nil, err
+		}
+		return NewSqliteStore(conn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database engine: %q", cfg.DB.ENGINE)
+	}
+}
+
+type postgresqlStore struct {
+	*Queries
+	pool *pgxpool.Pool
+}
+
+func NewPostgresqlStore(pool *pgxpool.Pool) Store {
+	return &postgresqlStore{
+		Queries: New(pool),
+		pool:    pool,
+	}
+}
+
+func (s *postgresqlStore) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+func (s *postgresqlStore) Stats() PoolStats {
+	return NewPoolStats(s.pool)
+}
+
+func (s *postgresqlStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		return fn(s.WithTx(tx))
+	})
+}
+
+// sqliteStore adapts the sqlite queries to Querier. The sqlc overrides in
+// sqlc.yaml keep the generated types identical to the postgresql ones, so the
+// results only need a type conversion.
+type sqliteStore struct {
+	q  *sqlite.Queries
+	db *sql.DB
+}
+
+func NewSqliteStore(db *sql.DB) Store {
+	return &sqliteStore{
+		q:  sqlite.New(db),
+		db: db,
+	}
+}
+
+func (s *sqliteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *sqliteStore) Stats() PoolStats {
+	stat := s.db.Stats()
+	return PoolStats{
+		AcquireCount:            stat.WaitCount,
+		AcquireDuration:         stat.WaitDuration,
+		AcquiredConns:           int32(stat.InUse),
+		IdleConns:               int32(stat.Idle),
+		MaxConns:                int32(stat.MaxOpenConnections),
+		TotalConns:              int32(stat.OpenConnections),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeClosed,
+		MaxIdleDestroyCount:     stat.MaxIdleClosed + stat.MaxIdleTimeClosed,
+	}
+}
+
+func (s *sqliteStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// no-op once committed, also releases the connection when fn panics
+	defer tx.Rollback()
+
+	if err := fn(&sqliteStore{q: s.q.WithTx(tx), db: s.db}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sqliteErr maps the database/sql errors onto the pgx ones so callers only
+// deal with a single set of sentinel errors
+func sqliteErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	return err
+}
+
+func (s *sqliteStore) AssignRole(ctx context.Context, arg AssignRoleParams) error {
+	return sqliteErr(s.q.AssignRole(ctx, sqlite.AssignRoleParams(arg)))
+}
+
+func (s *sqliteStore) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error) {
+	author, err := s.q.CreateAuthor(ctx, sqlite.CreateAuthorParams(arg))
+	return Author(author), sqliteErr(err)
+}
+
+func (s *sqliteStore) CreatePermission(ctx context.Context, name string) (Permission, error) {
+	permission, err := s.q.CreatePermission(ctx, name)
+	return Permission(permission), sqliteErr(err)
+}
+
+func (s *sqliteStore) CreateRole(ctx context.Context, name string) (Role, error) {
+	role, err := s.q.CreateRole(ctx, name)
+	return Role(role), sqliteErr(err)
+}
+
+func (s *sqliteStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	user, err := s.q.CreateUser(ctx, sqlite.CreateUserParams(arg))
+	return User(user), sqliteErr(err)
+}
+
+func (s *sqliteStore) DeleteAuthor(ctx context.Context, id int32) error {
+	return sqliteErr(s.q.DeleteAuthor(ctx, id))
+}
+
+func (s *sqliteStore) DeleteUser(ctx context.Context, id int32) error {
+	return sqliteErr(s.q.DeleteUser(ctx, id))
+}
+
+func (s *sqliteStore) GetAuthor(ctx context.Context, id int32) (Author, error) {
+	author, err := s.q.GetAuthor(ctx, id)
+	return Author(author), sqliteErr(err)
+}
+
+func (s *sqliteStore) GetPermissionByName(ctx context.Context, name string) (Permission, error) {
+	permission, err := s.q.GetPermissionByName(ctx, name)
+	return Permission(permission), sqliteErr(err)
+}
+
+func (s *sqliteStore) GetRoleByName(ctx context.Context, name string) (Role, error) {
+	role, err := s.q.GetRoleByName(ctx, name)
+	return Role(role), sqliteErr(err)
+}
+
+func (s *sqliteStore) GetUser(ctx context.Context, id int32) (User, error) {
+	user, err := s.q.GetUser(ctx, id)
+	return User(user), sqliteErr(err)
+}
+
+func (s *sqliteStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	user, err := s.q.GetUserByEmail(ctx, email)
+	return User(user), sqliteErr(err)
+}
+
+func (s *sqliteStore) GrantPermission(ctx context.Context, arg GrantPermissionParams) error {
+	return sqliteErr(s.q.GrantPermission(ctx, sqlite.GrantPermissionParams(arg)))
+}
+
+func (s *sqliteStore) ListAuthorsByID(ctx context.Context, arg ListAuthorsByIDParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByID(ctx, sqlite.ListAuthorsByIDParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByIDAfter(ctx context.Context, arg ListAuthorsByIDAfterParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByIDAfter(ctx, sqlite.ListAuthorsByIDAfterParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByIDDesc(ctx context.Context, arg ListAuthorsByIDDescParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByIDDesc(ctx, sqlite.ListAuthorsByIDDescParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByIDDescAfter(ctx context.Context, arg ListAuthorsByIDDescAfterParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByIDDescAfter(ctx, sqlite.ListAuthorsByIDDescAfterParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByName(ctx context.Context, arg ListAuthorsByNameParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByName(ctx, sqlite.ListAuthorsByNameParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByNameAfter(ctx context.Context, arg ListAuthorsByNameAfterParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByNameAfter(ctx, sqlite.ListAuthorsByNameAfterParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByNameDesc(ctx context.Context, arg ListAuthorsByNameDescParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByNameDesc(ctx, sqlite.ListAuthorsByNameDescParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListAuthorsByNameDescAfter(ctx context.Context, arg ListAuthorsByNameDescAfterParams) ([]Author, error) {
+	authors, err := s.q.ListAuthorsByNameDescAfter(ctx, sqlite.ListAuthorsByNameDescAfterParams(arg))
+	return fromSqliteAuthors(authors), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUserPermissions(ctx context.Context, userID int32) ([]string, error) {
+	permissions, err := s.q.ListUserPermissions(ctx, userID)
+	return permissions, sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByID(ctx context.Context, arg ListUsersByIDParams) ([]User, error) {
+	users, err := s.q.ListUsersByID(ctx, sqlite.ListUsersByIDParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByIDAfter(ctx context.Context, arg ListUsersByIDAfterParams) ([]User, error) {
+	users, err := s.q.ListUsersByIDAfter(ctx, sqlite.ListUsersByIDAfterParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByIDDesc(ctx context.Context, arg ListUsersByIDDescParams) ([]User, error) {
+	users, err := s.q.ListUsersByIDDesc(ctx, sqlite.ListUsersByIDDescParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByIDDescAfter(ctx context.Context, arg ListUsersByIDDescAfterParams) ([]User, error) {
+	users, err := s.q.ListUsersByIDDescAfter(ctx, sqlite.ListUsersByIDDescAfterParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByName(ctx context.Context, arg ListUsersByNameParams) ([]User, error) {
+	users, err := s.q.ListUsersByName(ctx, sqlite.ListUsersByNameParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByNameAfter(ctx context.Context, arg ListUsersByNameAfterParams) ([]User, error) {
+	users, err := s.q.ListUsersByNameAfter(ctx, sqlite.ListUsersByNameAfterParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByNameDesc(ctx context.Context, arg ListUsersByNameDescParams) ([]User, error) {
+	users, err := s.q.ListUsersByNameDesc(ctx, sqlite.ListUsersByNameDescParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) ListUsersByNameDescAfter(ctx context.Context, arg ListUsersByNameDescAfterParams) ([]User, error) {
+	users, err := s.q.ListUsersByNameDescAfter(ctx, sqlite.ListUsersByNameDescAfterParams(arg))
+	return fromSqliteUsers(users), sqliteErr(err)
+}
+
+func (s *sqliteStore) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error) {
+	author, err := s.q.UpdateAuthor(ctx, sqlite.UpdateAuthorParams(arg))
+	return Author(author), sqliteErr(err)
+}
+
+func (s *sqliteStore) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	user, err := s.q.UpdateUser(ctx, sqlite.UpdateUserParams(arg))
+	return User(user), sqliteErr(err)
+}
+
+func fromSqliteAuthors(rows []sqlite.Author) []Author {
+	items := make([]Author, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, Author(row))
+	}
+	return items
+}
+
+func fromSqliteUsers(rows []sqlite.User) []User {
+	items := make([]User, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, User(row))
+	}
+	return items
+}
+
+var _ Store = (*postgresqlStore)(nil)
+var _ Store = (*sqliteStore)(nil)
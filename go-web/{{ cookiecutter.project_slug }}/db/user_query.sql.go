@@ -11,17 +11,28 @@ import (
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-  name
+  name, email, password_hash
 ) VALUES (
-  $1
+  $1, $2, $3
 )
-RETURNING id, name
+RETURNING id, name, email, password_hash
 `
 
-func (q *Queries) CreateUser(ctx context.Context, name string) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, name)
+type CreateUserParams struct {
+	Name         string
+	Email        string
+	PasswordHash string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Email, arg.PasswordHash)
 	var i User
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
 	return i, err
 }
 
@@ -36,24 +47,334 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, name FROM users
+SELECT id, name, email, password_hash FROM users
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
 	row := q.db.QueryRow(ctx, getUser, id)
 	var i User
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, password_hash FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
 	return i, err
 }
 
-const listUsers = `-- name: ListUsers :many
-SELECT id, name FROM users
-ORDER BY name
+const listUsersByID = `-- name: ListUsersByID :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY id
+LIMIT $2::bigint
+`
+
+type ListUsersByIDParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByID(ctx context.Context, arg ListUsersByIDParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByID, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDAfter = `-- name: ListUsersByIDAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND id > $2::int
+ORDER BY id
+LIMIT $3::bigint
+`
+
+type ListUsersByIDAfterParams struct {
+	NamePrefix string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDAfter(ctx context.Context, arg ListUsersByIDAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByIDAfter, arg.NamePrefix, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDDesc = `-- name: ListUsersByIDDesc :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY id DESC
+LIMIT $2::bigint
+`
+
+type ListUsersByIDDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDDesc(ctx context.Context, arg ListUsersByIDDescParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByIDDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDDescAfter = `-- name: ListUsersByIDDescAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND id < $2::int
+ORDER BY id DESC
+LIMIT $3::bigint
+`
+
+type ListUsersByIDDescAfterParams struct {
+	NamePrefix string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByIDDescAfter(ctx context.Context, arg ListUsersByIDDescAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByIDDescAfter, arg.NamePrefix, arg.BeforeID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByName = `-- name: ListUsersByName :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY name, id
+LIMIT $2::bigint
+`
+
+type ListUsersByNameParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+// the List*By* queries are the first keyset pages of internal/pagination,
+// the List*By*After ones the pages after the cursor
+func (q *Queries) ListUsersByName(ctx context.Context, arg ListUsersByNameParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByName, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameAfter = `-- name: ListUsersByNameAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND (name, id) > ($2::text, $3::int)
+ORDER BY name, id
+LIMIT $4::bigint
+`
+
+type ListUsersByNameAfterParams struct {
+	NamePrefix string
+	AfterName  string
+	AfterID    int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameAfter(ctx context.Context, arg ListUsersByNameAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByNameAfter,
+		arg.NamePrefix,
+		arg.AfterName,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameDesc = `-- name: ListUsersByNameDesc :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+ORDER BY name DESC, id DESC
+LIMIT $2::bigint
+`
+
+type ListUsersByNameDescParams struct {
+	NamePrefix string
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameDesc(ctx context.Context, arg ListUsersByNameDescParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByNameDesc, arg.NamePrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByNameDescAfter = `-- name: ListUsersByNameDescAfter :many
+SELECT id, name, email, password_hash FROM users
+WHERE name LIKE $1::text || '%' ESCAPE '\'
+  AND (name, id) < ($2::text, $3::int)
+ORDER BY name DESC, id DESC
+LIMIT $4::bigint
 `
 
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, listUsers)
+type ListUsersByNameDescAfterParams struct {
+	NamePrefix string
+	BeforeName string
+	BeforeID   int32
+	RowLimit   int64
+}
+
+func (q *Queries) ListUsersByNameDescAfter(ctx context.Context, arg ListUsersByNameDescAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByNameDescAfter,
+		arg.NamePrefix,
+		arg.BeforeName,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +382,12 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	var items []User
 	for rows.Next() {
 		var i User
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -72,11 +398,11 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const updateUser = `-- name: UpdateUser :exec
+const updateUser = `-- name: UpdateUser :one
 UPDATE users
 set name = $1
 WHERE id = $2
-RETURNING id, name
+RETURNING id, name, email, password_hash
 `
 
 type UpdateUserParams struct {
@@ -84,7 +410,14 @@ type UpdateUserParams struct {
 	ID   int32
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.Exec(ctx, updateUser, arg.Name, arg.ID)
-	return err
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUser, arg.Name, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+	)
+	return i, err
 }
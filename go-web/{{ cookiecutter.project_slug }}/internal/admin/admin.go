@@ -0,0 +1,102 @@
+// Package admin serves the debug and runtime endpoints on their own
+// listener, cfg.ADMIN, so they are never exposed on the public port.
+//
+// Routes:
+// - /debug/pprof/: the profiles of net/http/pprof
+// - /debug/vars: the expvar variables
+// - /metrics: the prometheus metrics of the metrics.Module, the default
+// registry when the app has none
+// - /config: the config, the secrets redacted
+// - /db/stats: the connection pool statistics, when the app has a db.Store
+// - /log/level: GET the level of the logger, PUT {"level": "debug"} to
+// change it until the next restart
+package admin
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"exampleproj/config"
+	"exampleproj/db"
+	"exampleproj/internal/app"
+	"exampleproj/internal/shutdown"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Module provides the admin Server of cfg.ADMIN
+var Module = fx.Module("admin",
+	fx.Provide(
+		fx.Annotate(
+			NewServer,
+			fx.ParamTags(``, ``, ``, ``, ``, `optional:"true"`, `optional:"true"`),
+		),
+	),
+)
+
+// Server is the admin http server, apart from the public one
+type Server struct {
+	*http.Server
+}
+
+// NewServer returns the admin server of cfg.ADMIN, nil when it's disabled.
+// It's stopped in the last phase of the shutdown so a stuck shutdown can
+// still be profiled.
+func NewServer(lc fx.Lifecycle, sugar *zap.SugaredLogger, cfg *config.Config, level zap.AtomicLevel, coordinator *shutdown.Coordinator, gatherer prometheus.Gatherer, store db.Store) *Server {
+	if !cfg.ADMIN.ENABLED {
+		return nil
+	}
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(cfg.ADMIN.ADDR, cfg.ADMIN.PORT),
+		Handler:           NewHandler(cfg, level, gatherer, store),
+		ReadHeaderTimeout: cfg.App.READ_HEADER_TIMEOUT,
+		// no WriteTimeout, the cpu profiles and traces take their
+		// ?seconds= to answer
+	}
+
+	app.Serve(lc, sugar, coordinator, app.ServeOptions{
+		Name:   "admin server",
+		Server: server,
+		Phase:  shutdown.PhaseWorkers,
+	})
+
+	return &Server{server}
+}
+
+// NewHandler routes the admin endpoints, /metrics serves gatherer or the
+// default registry when it's nil. /db/stats is only routed with a store.
+func NewHandler(cfg *config.Config, level zap.AtomicLevel, gatherer prometheus.Gatherer, store db.Store) http.Handler {
+	metrics := promhttp.Handler()
+	if gatherer != nil {
+		metrics = promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+	}
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+
+	r.Mount("/debug", middleware.Profiler())
+	r.Handle("/metrics", metrics)
+	r.Get("/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(cfg.Redacted())
+	})
+	// AtomicLevel serves GET and PUT
+	r.Handle("/log/level", level)
+	if store != nil {
+		r.Get("/db/stats", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(store.Stats())
+		})
+	}
+
+	return r
+}
@@ -1,75 +1,336 @@
 package app
 
 import (
-	"exampleproj/routers/schemas"
+	"context"
 	"encoding/json"
+	"errors"
+	"exampleproj/db"
+	"exampleproj/internal/pagination"
+	"exampleproj/routers/schemas"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrorCategory groups error codes by their cause
+type ErrorCategory string
+
+const (
+	CategoryValidation  ErrorCategory = "validation"
+	CategoryAuth        ErrorCategory = "auth"
+	CategoryNotFound    ErrorCategory = "not_found"
+	CategoryConflict    ErrorCategory = "conflict"
+	CategoryRateLimit   ErrorCategory = "rate_limit"
+	CategoryUnavailable ErrorCategory = "unavailable"
+	CategoryInternal    ErrorCategory = "internal"
+)
+
+// ErrorDefinition is an entry of the error catalog
+type ErrorDefinition struct {
+	HTTPStatus int
+	// Message is the english template, its params {0}, {1}... are filled by
+	// NewMyErrorf. The other locales are in errorCatalogTranslations
+	Message  string
+	Category ErrorCategory
+}
+
+// PublicError marks a cause written for the clients, ex. a validation
+// message, to be appended to the message of the MyError. The other causes,
+// ex. the driver errors, are only logged with the request.
+type PublicError struct {
+	err error
+}
+
+// Public marks err as safe to show to the clients
+func Public(err error) error {
+	return &PublicError{err: err}
+}
+
+func (p *PublicError) Error() string {
+	return p.err.Error()
+}
+
+func (p *PublicError) Unwrap() error {
+	return p.err
+}
+
 type MyError struct {
 	schemas.BasicError
 	errs     validator.ValidationErrors
+	fields   []schemas.FieldError
+	params   []string
 	httpCode int
+	category ErrorCategory
+	cause    error
 }
 
+// NewMyError builds the error of the catalog entry code, err is appended to
+// the message when it's a PublicError
 func NewMyError(err error, code int) *MyError {
-	return NewMyErrorWithHTTPCode(err, code, http.StatusBadRequest)
+	return NewMyErrorWithHTTPCode(err, code, LookupError(code).HTTPStatus)
 }
 
+// NewMyErrorWithHTTPCode is NewMyError with the catalog http status overridden
 func NewMyErrorWithHTTPCode(err error, code int, httpCode int) *MyError {
-	verr, ok := err.(validator.ValidationErrors)
-	if !ok {
-		verr = nil
+	merr := &MyError{
+		httpCode:   httpCode,
+		category:   LookupError(code).Category,
+		cause:      err,
+		BasicError: schemas.BasicError{Code: code},
+	}
+	errors.As(err, &merr.errs)
+	return merr.localize(universal.GetFallback())
+}
+
+// NewMyErrorf builds the error of the catalog entry code with its message
+// template filled by params
+func NewMyErrorf(code int, params ...string) *MyError {
+	def := LookupError(code)
+	merr := &MyError{
+		params:     params,
+		httpCode:   def.HTTPStatus,
+		category:   def.Category,
+		BasicError: schemas.BasicError{Code: code},
 	}
+	return merr.localize(universal.GetFallback())
+}
+
+// NewMyErrorWithFields builds the error of the catalog entry code carrying
+// field errors found by other means than the validator, ex. the openapi
+// request validation. Their messages aren't translated.
+func NewMyErrorWithFields(err error, code int, fields []schemas.FieldError) *MyError {
+	merr := NewMyError(err, code)
+	merr.fields = fields
+	return merr.localize(universal.GetFallback())
+}
 
-	msg := fmt.Sprintf("%s: %s", GetMessageFromErrorCodeMap(code), err.Error())
-	return &MyError{
-		errs:     verr,
-		httpCode: httpCode,
-		BasicError: schemas.BasicError{
-			Code: code, Message: msg,
-		}}
+// Localize returns a copy of the error whose message and field errors are
+// in the locale of trans
+func (m *MyError) Localize(trans ut.Translator) *MyError {
+	localized := *m
+	return localized.localize(trans)
+}
+
+func (m *MyError) localize(trans ut.Translator) *MyError {
+	m.Message = translateCode(trans, m.Code, m.params...)
+
+	if m.errs != nil {
+		fields := make([]schemas.FieldError, 0, len(m.errs))
+		for _, fe := range m.errs {
+			fields = append(fields, schemas.FieldError{
+				Field:   fe.Field(),
+				Tag:     fe.Tag(),
+				Message: fieldErrorMessage(fe, trans),
+			})
+		}
+		m.Errors = &fields
+		return m
+	}
+
+	if m.fields != nil {
+		m.Errors = &m.fields
+		return m
+	}
+
+	var public *PublicError
+	if errors.As(m.cause, &public) {
+		m.Message = fmt.Sprintf("%s: %s", m.Message, public.Error())
+	}
+	return m
 }
 
 func (m *MyError) Error() string {
 	return fmt.Sprintf("%d: %s", m.Code, m.Message)
 }
 
-func RenderError(w http.ResponseWriter, err error) {
+func (m *MyError) Unwrap() error {
+	return m.cause
+}
+
+func (m *MyError) HTTPStatus() int {
+	return m.httpCode
+}
+
+func (m *MyError) Category() ErrorCategory {
+	return m.category
+}
+
+// AsMyError maps err onto the error catalog, errors which are not a MyError
+// are classified by their type and fall back to ErrorCodeUnknown
+func AsMyError(err error) *MyError {
 	var merr *MyError
-	var ok bool
+	if errors.As(err, &merr) {
+		return merr
+	}
 
-	merr, ok = err.(*MyError)
-	if !ok {
-		merr = NewMyError(err, ErrorCodeUnknown)
+	var verr validator.ValidationErrors
+	var perr *pagination.ParamError
+	switch {
+	case errors.As(err, &verr):
+		return NewMyError(verr, ErrorCodeValidation)
+	case errors.As(err, &perr):
+		return NewMyErrorf(ErrorCodeInvalidParam, perr.Param)
+	case errors.Is(err, pgx.ErrNoRows):
+		return NewMyError(err, ErrorCodeNotFound)
+	case db.IsUniqueViolation(err):
+		return NewMyError(err, ErrorCodeConflict)
+	case isTimeout(err):
+		return NewMyError(err, ErrorCodeUnavailable)
 	}
+	return NewMyError(err, ErrorCodeUnknown)
+}
 
+// RenderError writes err as a schemas.BasicError, or as a RFC 7807 document
+// when the request negotiates ErrorFormatProblem. The messages are in the
+// locale of the Accept-Language header.
+func RenderError(w http.ResponseWriter, r *http.Request, err error) {
+	trans := TranslatorFromRequest(r)
+	merr := AsMyError(err).Localize(trans)
+	if r != nil {
+		recordError(r.Context(), merr)
+	}
+
+	if NegotiateErrorFormat(r) == ErrorFormatProblem {
+		var instance string
+		if r != nil {
+			instance = r.URL.Path
+		}
+		w.Header().Set("Content-Type", mimeProblem)
+		w.WriteHeader(merr.httpCode)
+		json.NewEncoder(w).Encode(merr.Problem(trans, instance))
+		return
+	}
+
+	w.Header().Set("Content-Type", mimeJSON)
 	w.WriteHeader(merr.httpCode)
 	json.NewEncoder(w).Encode(merr)
 }
 
-func GetMessageFromErrorCodeMap(code int) string {
-	if msg, ok := errorCodeMessageMap[code]; ok {
+type renderedErrorKey struct{}
+
+// WithRenderedError returns a context in which RenderError keeps the cause
+// of the error it renders, the returned func reads it once the request is
+// served so the hidden causes are logged server side
+func WithRenderedError(ctx context.Context) (context.Context, func() error) {
+	var cause error
+	return context.WithValue(ctx, renderedErrorKey{}, &cause), func() error {
+		return cause
+	}
+}
+
+func recordError(ctx context.Context, merr *MyError) {
+	if cause, ok := ctx.Value(renderedErrorKey{}).(*error); ok && merr.cause != nil {
+		*cause = merr.cause
+	}
+}
+
+// LookupError returns the catalog entry of code, unknown codes resolve to
+// the ErrorCodeUnknown entry
+func LookupError(code int) ErrorDefinition {
+	if def, ok := errorCatalog[code]; ok {
+		return def
+	}
+	return errorCatalog[ErrorCodeUnknown]
+}
+
+// DecodeError classifies the error of decoding a request body, the json
+// errors are described to the client without the go types of the fields.
+// The bodies are decoded where they are read, an EOF elsewhere, ex. a
+// dropped connection, is an internal error.
+func DecodeError(err error) *MyError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		return NewMyError(Public(errors.New("unexpected end of the body")), ErrorCodeInvalidBody)
+	case errors.As(err, &syntaxErr):
+		return NewMyError(Public(syntaxErr), ErrorCodeInvalidBody)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return NewMyError(Public(fmt.Errorf("field %s has the wrong type", typeErr.Field)), ErrorCodeInvalidBody)
+	}
+	return NewMyError(err, ErrorCodeInvalidBody)
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return true
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr) && nerr.Timeout()
+}
+
+// fieldErrorMessage translates the failed rule, the rules without
+// translation are described in english
+func fieldErrorMessage(fe validator.FieldError, trans ut.Translator) string {
+	if msg := fe.Translate(trans); msg != fe.Error() {
 		return msg
 	}
-	return ""
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' rule", fe.Field(), fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag())
 }
 
 const (
 	ErrorCodeInvalidPassword int = 1000
-	ErrorCodeUnknown         int = 9999
+	ErrorCodeUserNotFound    int = 1001
+	ErrorCodeInvalidUserID   int = 1002
+	ErrorCodeEmailExists     int = 1003
+
+	ErrorCodeAuthorNotFound int = 1100
+
+	ErrorCodeInvalidCredentials int = 2000
+	ErrorCodeInvalidToken       int = 2001
+	ErrorCodeUnauthenticated    int = 2002
+	ErrorCodeForbidden          int = 2003
+
+	ErrorCodeValidation   int = 8000
+	ErrorCodeInvalidBody  int = 8001
+	ErrorCodeNotFound     int = 8002
+	ErrorCodeConflict     int = 8003
+	ErrorCodeUnavailable  int = 8004
+	ErrorCodeInvalidParam int = 8005
+	ErrorCodeRateLimited  int = 8006
+
+	ErrorCodeIdempotencyKeyReused  int = 8007
+	ErrorCodeIdempotencyInProgress int = 8008
+
+	ErrorCodeUnknown int = 9999
 )
 
-// errorCodeMessageMap is a error code map manager
-var errorCodeMessageMap = map[int]string{
+// errorCatalog declares every error code the api answers with
+var errorCatalog = map[int]ErrorDefinition{
 	// 1000 - 2000 for user relevant error codes
-	ErrorCodeInvalidPassword: "invalid password",
+	ErrorCodeInvalidPassword: {http.StatusBadRequest, "invalid password", CategoryValidation},
+	ErrorCodeUserNotFound:    {http.StatusNotFound, "user not found", CategoryNotFound},
+	ErrorCodeInvalidUserID:   {http.StatusBadRequest, "invalid user id", CategoryValidation},
+	ErrorCodeEmailExists:     {http.StatusConflict, "email already registered", CategoryConflict},
+
+	ErrorCodeAuthorNotFound: {http.StatusNotFound, "author not found", CategoryNotFound},
+
+	// 2000 - 3000 for authentication and authorization
+	ErrorCodeInvalidCredentials: {http.StatusUnauthorized, "invalid email or password", CategoryAuth},
+	ErrorCodeInvalidToken:       {http.StatusUnauthorized, "invalid token", CategoryAuth},
+	ErrorCodeUnauthenticated:    {http.StatusUnauthorized, "unauthenticated", CategoryAuth},
+	ErrorCodeForbidden:          {http.StatusForbidden, "forbidden", CategoryAuth},
+
+	// 8000 - 9000 for generic errors not bound to a resource
+	ErrorCodeValidation:   {http.StatusBadRequest, "validation failed", CategoryValidation},
+	ErrorCodeInvalidBody:  {http.StatusBadRequest, "invalid request body", CategoryValidation},
+	ErrorCodeNotFound:     {http.StatusNotFound, "resource not found", CategoryNotFound},
+	ErrorCodeConflict:     {http.StatusConflict, "resource already exists", CategoryConflict},
+	ErrorCodeUnavailable:  {http.StatusServiceUnavailable, "service temporarily unavailable", CategoryUnavailable},
+	ErrorCodeInvalidParam: {http.StatusBadRequest, "invalid parameter {0}", CategoryValidation},
+	ErrorCodeRateLimited:  {http.StatusTooManyRequests, "too many requests, retry in {0}s", CategoryRateLimit},
 
-	// 2000 - 3000 for xxx
+	ErrorCodeIdempotencyKeyReused:  {http.StatusConflict, "idempotency key already used by another request", CategoryConflict},
+	ErrorCodeIdempotencyInProgress: {http.StatusConflict, "the request of the idempotency key is in progress", CategoryConflict},
 
-	// database error
-	ErrorCodeUnknown: "unknown error",
+	ErrorCodeUnknown: {http.StatusInternalServerError, "unknown error", CategoryInternal},
 }
@@ -2,17 +2,23 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+
+	"exampleproj/config"
+	"exampleproj/internal/health"
+	"exampleproj/internal/shutdown"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/spf13/viper"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // NewHTTPServer creates and returns a new HTTP server.
@@ -20,49 +26,132 @@ import (
 // It takes in the following parameters:
 // - lc: an instance of fx.Lifecycle
 // - sugar: a pointer to a zap.SugaredLogger
-// - vp: a pointer to a viper.Viper
+// - cfg: the config, the server is set up by cfg.App
 // - handler: a pointer to a chi.Mux
+// - coordinator: the shutdown coordinator, the server stops in its http
+// phase and lets the in-flight requests finish
+// - registry: the optional health registry, its readiness fails in the
+// drain phase
 //
-// It returns a pointer to an http.Server.
-func NewHTTPServer(lc fx.Lifecycle, sugar *zap.SugaredLogger, vp *viper.Viper, handler *chi.Mux) *http.Server {
+// It returns a pointer to an http.Server, serving https and HTTP/2 when
+// cfg.App.TLS has a certificate, and h2c when cfg.App.H2C is set instead.
+func NewHTTPServer(lc fx.Lifecycle, sugar *zap.SugaredLogger, cfg *config.Config, handler *chi.Mux, coordinator *shutdown.Coordinator, registry *health.Registry) (*http.Server, error) {
+	conf := cfg.App
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", vp.Get("app.addr"), vp.Get("app.port")),
-		Handler: handler,
+		Addr:              net.JoinHostPort(conf.Addr, conf.Port),
+		Handler:           handler,
+		ReadTimeout:       conf.READ_TIMEOUT,
+		ReadHeaderTimeout: conf.READ_HEADER_TIMEOUT,
+		WriteTimeout:      conf.WRITE_TIMEOUT,
+		IdleTimeout:       conf.IDLE_TIMEOUT,
+		MaxHeaderBytes:    conf.MAX_HEADER_BYTES,
+	}
+
+	if conf.TLS.CERT_FILE != "" || conf.TLS.KEY_FILE != "" {
+		reloader, err := newCertReloader(conf.TLS.CERT_FILE, conf.TLS.KEY_FILE, conf.TLS.RELOAD_INTERVAL, sugar)
+		if err != nil {
+			return nil, fmt.Errorf("load the tls certificate: %w", err)
+		}
+		// ServeTLS adds h2 to the protocols
+		server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		}
+	} else if conf.H2C {
+		server.Handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: conf.IDLE_TIMEOUT})
+	}
+
+	if registry != nil {
+		coordinator.Register(shutdown.PhaseDrain, "readiness", func(ctx context.Context) error {
+			registry.Shutdown()
+			return nil
+		})
 	}
 
-	quit := make(chan os.Signal)
+	Serve(lc, sugar, coordinator, ServeOptions{
+		Name:   "http server",
+		Server: server,
+		Socket: conf.SOCKET,
+		Phase:  shutdown.PhaseHTTP,
+	})
+
+	return server, nil
+}
+
+// ServeOptions tells Serve how to run a server
+type ServeOptions struct {
+	// Name identifies the server in the logs
+	Name   string
+	Server *http.Server
+	// Socket is the unix socket to listen on instead of Server.Addr
+	Socket string
+	// Phase is the shutdown phase stopping the server
+	Phase shutdown.Phase
+}
+
+// Serve runs opts.Server with the app, https when it has a TLSConfig, and
+// stops it in opts.Phase of the shutdown, the in-flight requests finish
+// within the shutdown deadline
+func Serve(lc fx.Lifecycle, sugar *zap.SugaredLogger, coordinator *shutdown.Coordinator, opts ServeOptions) {
+	server := opts.Server
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			// listen before returning so the start fails when the address
+			// is taken
+			ln, err := listen(server.Addr, opts.Socket)
+			if err != nil {
+				return err
+			}
 
 			go func() {
 				// spawn the web server
-				sugar.Infof("start server: %s", server.Addr)
-				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					sugar.Fatalf("listen: %s", err.Error())
-				}
-
-				signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-				<-quit
+				sugar.Infow("start server", "server", opts.Name, "addr", ln.Addr().String(), "tls", server.TLSConfig != nil)
 
+				var err error
+				if server.TLSConfig != nil {
+					err = server.ServeTLS(ln, "", "")
+				} else {
+					err = server.Serve(ln)
+				}
+				if err != nil && err != http.ErrServerClosed {
+					sugar.Errorw("server stopped", "server", opts.Name, "error", err)
+				}
 			}()
 
-			return nil
-		},
-		OnStop: func(ctx context.Context) error {
-			sugar.Info("graceful shutdown")
-			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-			defer cancel()
-
-			if err := server.Shutdown(ctx); err != nil {
-				sugar.Fatalf("Server shutdown with error: %s", err.Error())
-			}
-
-			sugar.Info("shutdown complete..")
-			sugar.Sync()
 			return nil
 		}})
 
-	return server
+	coordinator.Register(opts.Phase, opts.Name, func(ctx context.Context) error {
+		if err := server.Shutdown(ctx); err != nil {
+			// the deadline is over, the remaining requests are cut
+			server.Close()
+			return fmt.Errorf("in-flight requests cut: %w", err)
+		}
+		return nil
+	})
+}
+
+// listen opens the unix socket when set, addr otherwise
+func listen(addr, socket string) (net.Listener, error) {
+	if socket == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	// the socket of a previous run which wasn't shut down fails the listen,
+	// it's removed unless the path is something else
+	info, err := os.Lstat(socket)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case info.Mode()&fs.ModeSocket == 0:
+		return nil, fmt.Errorf("%s exists and isn't a socket", socket)
+	default:
+		if err := os.Remove(socket); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", socket)
 }
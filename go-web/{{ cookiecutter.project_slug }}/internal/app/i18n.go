@@ -0,0 +1,129 @@
+package app
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	"golang.org/x/text/language"
+)
+
+// universal holds a translator per supported locale, english is the fallback
+var universal = newUniversalTranslator()
+
+// errorCatalogTranslations are the catalog messages of the locales other
+// than english, whose messages are the ones of errorCatalog. The templates
+// take their params as {0}, {1}...
+var errorCatalogTranslations = map[string]map[int]string{
+	"zh": {
+		ErrorCodeInvalidPassword: "密码无效",
+		ErrorCodeUserNotFound:    "用户不存在",
+		ErrorCodeInvalidUserID:   "用户 ID 无效",
+		ErrorCodeEmailExists:     "邮箱已被注册",
+
+		ErrorCodeAuthorNotFound: "作者不存在",
+
+		ErrorCodeInvalidCredentials: "邮箱或密码错误",
+		ErrorCodeInvalidToken:       "令牌无效",
+		ErrorCodeUnauthenticated:    "未登录",
+		ErrorCodeForbidden:          "没有权限",
+
+		ErrorCodeValidation:   "参数校验失败",
+		ErrorCodeInvalidBody:  "请求体无效",
+		ErrorCodeNotFound:     "资源不存在",
+		ErrorCodeConflict:     "资源已存在",
+		ErrorCodeUnavailable:  "服务暂时不可用",
+		ErrorCodeInvalidParam: "参数 {0} 无效",
+		ErrorCodeRateLimited:  "请求过于频繁，请在 {0} 秒后重试",
+
+		ErrorCodeIdempotencyKeyReused:  "幂等键已被其他请求使用",
+		ErrorCodeIdempotencyInProgress: "该幂等键的请求正在处理中",
+
+		ErrorCodeUnknown: "未知错误",
+	},
+}
+
+var placeholder = regexp.MustCompile(`\{\d+\}`)
+
+func newUniversalTranslator() *ut.UniversalTranslator {
+	english := en.New()
+	uni := ut.New(english, english, zh.New())
+
+	for code, def := range errorCatalog {
+		trans, _ := uni.GetTranslator("en")
+		if err := trans.Add(code, def.Message, false); err != nil {
+			panic(err)
+		}
+	}
+
+	for locale, messages := range errorCatalogTranslations {
+		trans, ok := uni.GetTranslator(locale)
+		if !ok {
+			panic("unsupported locale " + locale)
+		}
+		for code, msg := range messages {
+			if err := trans.Add(code, msg, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return uni
+}
+
+func registerValidatorTranslations(v *validator.Validate) error {
+	trans, _ := universal.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, trans); err != nil {
+		return err
+	}
+
+	trans, _ = universal.GetTranslator("zh")
+	return zh_translations.RegisterDefaultTranslations(v, trans)
+}
+
+// TranslatorFromRequest returns the translator of the preferred locale of
+// the Accept-Language header, falling back to english
+func TranslatorFromRequest(r *http.Request) ut.Translator {
+	if r == nil {
+		return universal.GetFallback()
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return universal.GetFallback()
+	}
+
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if trans, ok := universal.FindTranslator(strings.ReplaceAll(tag.String(), "-", "_"), base.String()); ok {
+			return trans
+		}
+	}
+	return universal.GetFallback()
+}
+
+// translateCode renders the catalog message of code in the locale of trans,
+// codes missing from the locale use the english message
+func translateCode(trans ut.Translator, code int, params ...string) string {
+	def := LookupError(code)
+	if _, ok := errorCatalog[code]; !ok {
+		code = ErrorCodeUnknown
+	}
+
+	// the templates panic on missing params
+	if n := len(placeholder.FindAllString(def.Message, -1)); len(params) < n {
+		params = append(params, make([]string, n-len(params))...)
+	}
+
+	msg, err := trans.T(code, params...)
+	if err != nil {
+		msg, _ = universal.GetFallback().T(code, params...)
+	}
+	return msg
+}
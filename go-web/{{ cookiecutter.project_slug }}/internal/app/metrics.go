@@ -0,0 +1,93 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"exampleproj/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the pyth clients are created per task, their metrics are shared
+var (
+	pythDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "pyth",
+		Name:      "request_duration_seconds",
+		Help:      "The latency of the requests to the Hermes api by endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+	pythFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "pyth",
+		Name:      "request_failures_total",
+		Help:      "The requests to the Hermes api which failed or did not answer 200, by endpoint.",
+	}, []string{"endpoint"})
+)
+
+// observePyth records a request sent at start, resp is the response of
+// http.Client.Do
+func observePyth(endpoint string, start time.Time, resp *http.Response, err error) {
+	pythDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode != http.StatusOK {
+		pythFailures.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// PythCollector exports the metrics of the PythAPIClient requests
+type PythCollector struct{}
+
+func NewPythCollector() *PythCollector {
+	return &PythCollector{}
+}
+
+func (PythCollector) Describe(ch chan<- *prometheus.Desc) {
+	pythDuration.Describe(ch)
+	pythFailures.Describe(ch)
+}
+
+func (PythCollector) Collect(ch chan<- prometheus.Metric) {
+	pythDuration.Collect(ch)
+	pythFailures.Collect(ch)
+}
+
+// HubCollector exports the Stats of a Hub on every scrape
+type HubCollector struct {
+	hub *Hub
+
+	clients *prometheus.Desc
+	dropped *prometheus.Desc
+}
+
+func NewHubCollector(hub *Hub) *HubCollector {
+	return &HubCollector{
+		hub: hub,
+		clients: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "websocket", "clients"),
+			"The websocket clients connected to the hub.",
+			nil, nil,
+		),
+		dropped: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "websocket", "dropped_messages_total"),
+			"The messages dropped by the hub, the buffer of the client was full.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *HubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clients
+	ch <- c.dropped
+}
+
+func (c *HubCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.hub.Stats()
+	ch <- prometheus.MustNewConstMetric(c.clients, prometheus.GaugeValue, float64(stats.Clients))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.Dropped))
+}
+
+var (
+	_ prometheus.Collector = (*PythCollector)(nil)
+	_ prometheus.Collector = (*HubCollector)(nil)
+)
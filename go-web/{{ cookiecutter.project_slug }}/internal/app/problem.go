@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"exampleproj/routers/schemas"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+)
+
+// ErrorFormat selects the document RenderError answers with
+type ErrorFormat string
+
+const (
+	// ErrorFormatBasic renders schemas.BasicError as application/json
+	ErrorFormatBasic ErrorFormat = "basic"
+	// ErrorFormatProblem renders RFC 7807 documents as application/problem+json
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+const (
+	mimeJSON    = "application/json"
+	mimeProblem = "application/problem+json"
+)
+
+// ParseErrorFormat parses the APP_ERROR_FORMAT setting, "basic" or "problem"
+func ParseErrorFormat(s string) (ErrorFormat, error) {
+	switch format := ErrorFormat(s); format {
+	case ErrorFormatBasic, ErrorFormatProblem:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid error format %q, want %q or %q", s, ErrorFormatBasic, ErrorFormatProblem)
+	}
+}
+
+type errorFormatKey struct{}
+
+// ErrorFormatMiddleware sets the error format of the requests which don't
+// ask for one through their Accept header
+func ErrorFormatMiddleware(format ErrorFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), errorFormatKey{}, format)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// NegotiateErrorFormat picks the error format of the request, the Accept
+// header wins over the configured default
+func NegotiateErrorFormat(r *http.Request) ErrorFormat {
+	if r == nil {
+		return ErrorFormatBasic
+	}
+
+	if format, ok := acceptedErrorFormat(r.Header.Values("Accept")); ok {
+		return format
+	}
+
+	if format, ok := r.Context().Value(errorFormatKey{}).(ErrorFormat); ok {
+		return format
+	}
+	return ErrorFormatBasic
+}
+
+// acceptedErrorFormat returns the format of the preferred media type among
+// application/problem+json and application/json, wildcards express no
+// preference
+func acceptedErrorFormat(accept []string) (ErrorFormat, bool) {
+	var format ErrorFormat
+	best := 0.0
+
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+
+			var candidate ErrorFormat
+			switch mediaType {
+			case mimeProblem:
+				candidate = ErrorFormatProblem
+			case mimeJSON:
+				candidate = ErrorFormatBasic
+			default:
+				continue
+			}
+
+			// on a tie problem+json is the more specific choice
+			if q > best || (q == best && q > 0 && candidate == ErrorFormatProblem) {
+				format, best = candidate, q
+			}
+		}
+	}
+	return format, best > 0
+}
+
+// Problem converts the error into its RFC 7807 document, the title is in the
+// locale of trans and instance is the path of the failed request
+func (m *MyError) Problem(trans ut.Translator, instance string) schemas.Problem {
+	problem := schemas.Problem{
+		Type:   fmt.Sprintf("urn:exampleproj:error:%d", m.Code),
+		Title:  translateCode(trans, m.Code, m.params...),
+		Status: m.httpCode,
+		Code:   m.Code,
+		Errors: m.Errors,
+	}
+	if m.Message != "" {
+		problem.Detail = &m.Message
+	}
+	if instance != "" {
+		problem.Instance = &instance
+	}
+	return problem
+}
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type Price struct {
@@ -71,7 +74,9 @@ func (p *PythAPIClient) buildGetLatestPricesURL(ids []string) string {
 func (p *PythAPIClient) GetLatestPrices(ids []string) (*ApiResponse, error) {
 	url := p.buildGetLatestPricesURL(ids)
 
+	start := time.Now()
 	resp, err := http.Get(url)
+	observePyth("latest_prices", start, resp, err)
 	if err != nil {
 		return nil, err
 	}
@@ -88,3 +93,29 @@ func (p *PythAPIClient) GetLatestPrices(ids []string) (*ApiResponse, error) {
 	return &apiResp, nil
 
 }
+
+// Live checks the Hermes api is up
+func (p *PythAPIClient) Live(ctx context.Context) error {
+	baseURL, err := url.Parse(p.baseURL)
+	if err != nil {
+		return err
+	}
+	baseURL.Path = "/live"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	observePyth("live", start, resp, err)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pyth api answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certReloader serves the certificate of certFile and keyFile, reloaded
+// when they change on disk so the renewed certificates are picked up
+// without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	logger   *zap.SugaredLogger
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// newCertReloader loads the certificate, the files are checked again at
+// most once per interval
+func newCertReloader(certFile, keyFile string, interval time.Duration, logger *zap.SugaredLogger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		logger:   logger,
+	}
+
+	modTime, err := r.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) >= r.interval {
+		r.reload()
+	}
+	return r.cert, nil
+}
+
+// reload loads the files when they changed, the current certificate is kept
+// when they can't be loaded, ex. while they are being written
+func (r *certReloader) reload() {
+	r.checkedAt = time.Now()
+
+	modTime, err := r.lastModified()
+	if err != nil {
+		r.logger.Warnw("failed to check the tls certificate", "cert_file", r.certFile, "error", err)
+		return
+	}
+	if !modTime.After(r.modTime) {
+		return
+	}
+
+	if err := r.load(modTime); err != nil {
+		r.logger.Warnw("failed to reload the tls certificate", "cert_file", r.certFile, "error", err)
+		return
+	}
+	r.logger.Infow("tls certificate reloaded", "cert_file", r.certFile)
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	r.checkedAt = time.Now()
+	return nil
+}
+
+// lastModified is the latest modification of the files
+func (r *certReloader) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
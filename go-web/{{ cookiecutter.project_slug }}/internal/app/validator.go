@@ -0,0 +1,37 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared by the handlers, it caches the struct metadata and holds
+// the translations of the validation messages
+var validate = newValidator()
+
+// Validator returns the validator reporting fields by their json name, so
+// the field errors of a response match the request body
+func Validator() *validator.Validate {
+	return validate
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	if err := registerValidatorTranslations(v); err != nil {
+		panic(err)
+	}
+	return v
+}
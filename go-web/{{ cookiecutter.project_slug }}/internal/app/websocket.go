@@ -5,7 +5,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -42,6 +45,8 @@ type Client struct {
 	// The websocket connection.
 	conn *websocket.Conn
 
+	// mu guards submap, filled by Subscribe while readPump dispatches
+	mu     sync.Mutex
 	submap map[string]*extensions.BrokerChannelSubscription
 
 	// Buffered channel of outbound messages.
@@ -64,7 +69,16 @@ func NewWSClient(hub *Hub, conn *websocket.Conn, bufferSize int) *Client {
 	client.conn.SetReadDeadline(time.Now().Add(pongWait))
 	client.conn.SetPongHandler(func(string) error { client.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
 
-	client.hub.register <- client
+	select {
+	case client.hub.register <- client:
+		// the only reader of the connection, Subscribe adds the channels
+		// it dispatches to
+		go client.readPump()
+	case <-client.hub.done:
+		// the hub is shut down
+		client.goAway()
+		client.conn.Close()
+	}
 
 	return client
 }
@@ -115,12 +129,12 @@ func (c *Client) Publish(_ context.Context, channel string, bm extensions.Broker
 func (c *Client) readPump() {
 
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
-	c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
 	for {
 		_, message, err := c.conn.ReadMessage()
 		if err != nil {
@@ -134,8 +148,14 @@ func (c *Client) readPump() {
 		payload := map[string]interface{}{}
 		_ = json.Unmarshal(message, &payload)
 
-		// TODO: error handling?
-		sub := c.submap[payload["event"].(string)]
+		event, _ := payload["event"].(string)
+		c.mu.Lock()
+		sub := c.submap[event]
+		c.mu.Unlock()
+		if sub == nil {
+			// not subscribed, or not a json event
+			continue
+		}
 
 		sub.TransmitReceivedMessage(extensions.NewAcknowledgeableBrokerMessage(
 			extensions.BrokerMessage{
@@ -143,7 +163,10 @@ func (c *Client) readPump() {
 			},
 			NoopAcknowledgementHandler{},
 		))
-		c.hub.broadcast <- message
+		select {
+		case c.hub.broadcast <- message:
+		case <-c.hub.done:
+		}
 	}
 }
 
@@ -157,8 +180,9 @@ func (c *Client) Subscribe(ctx context.Context, channel string) (extensions.Brok
 
 	// TODO: fix bugs -- distinct dispatch message for each channel
 
+	c.mu.Lock()
 	c.submap[channel] = &sub
-	go c.readPump()
+	c.mu.Unlock()
 
 	sub.WaitForCancellationAsync(func() {
 		c.Close()
@@ -172,6 +196,13 @@ func (c *Client) Context() context.Context {
 	return c.context
 }
 
+// goAway tells the peer the server is shutting down, WriteControl is safe
+// to call concurrently with the writes of Publish
+func (c *Client) goAway() error {
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	return c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+}
+
 func (c *Client) Close() error {
 	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 
@@ -188,6 +219,30 @@ type Hub struct {
 	register chan *Client
 	// Unregister requests from clients.
 	unregister chan *Client
+	// quit starts the shutdown, the clients are sent a close frame
+	quit chan struct{}
+	// kill closes the connections of the clients still there
+	kill chan struct{}
+	// done is closed once the clients are gone and Run returned
+	done chan struct{}
+	// connected mirrors len(clients) for the readers outside of Run
+	connected atomic.Int64
+	// dropped counts the messages not sent to a client, its buffer was full
+	dropped atomic.Uint64
+}
+
+// HubStats is a snapshot of the hub for the metrics
+type HubStats struct {
+	Clients int
+	Dropped uint64
+}
+
+// Stats is safe to call while the hub runs
+func (h *Hub) Stats() HubStats {
+	return HubStats{
+		Clients: int(h.connected.Load()),
+		Dropped: h.dropped.Load(),
+	}
 }
 
 func NewHub() *Hub {
@@ -196,18 +251,45 @@ func NewHub() *Hub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
+		quit:       make(chan struct{}),
+		kill:       make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 }
 
 func (h *Hub) Run() {
+	closing := false
 	for {
 		select {
 		case client := <-h.register:
 			h.clients[client] = true
+			h.connected.Store(int64(len(h.clients)))
+			if closing {
+				client.goAway()
+			}
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
+				h.connected.Store(int64(len(h.clients)))
+			}
+			if closing && len(h.clients) == 0 {
+				close(h.done)
+				return
+			}
+		case <-h.quit:
+			closing = true
+			for client := range h.clients {
+				client.goAway()
+			}
+			if len(h.clients) == 0 {
+				close(h.done)
+				return
+			}
+		case <-h.kill:
+			// their readPump unregisters them
+			for client := range h.clients {
+				client.conn.Close()
 			}
 		case message := <-h.broadcast:
 			for client := range h.clients {
@@ -216,12 +298,39 @@ func (h *Hub) Run() {
 				default:
 					close(client.send)
 					delete(h.clients, client)
+					h.dropped.Add(1)
 				}
 			}
+			h.connected.Store(int64(len(h.clients)))
 		}
 	}
 }
 
+// Shutdown sends a close frame to the clients and waits for them to leave,
+// the connections still open when ctx is done are closed
+func (h *Hub) Shutdown(ctx context.Context) error {
+	select {
+	case h.quit <- struct{}{}:
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	select {
+	case h.kill <- struct{}{}:
+	case <-h.done:
+		return nil
+	}
+	return fmt.Errorf("websocket clients left open: %w", ctx.Err())
+}
+
 // NOTE: do I need a ack mechanism?
 var _ extensions.BrokerAcknowledgment = (*NoopAcknowledgementHandler)(nil)
 
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"exampleproj/config"
+	"exampleproj/db"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// RoleAdmin is the role seeded with every permission
+const RoleAdmin = "admin"
+
+// GrantAdmins assigns RoleAdmin to the users registered with the emails, it
+// returns the emails no user is registered with
+func GrantAdmins(ctx context.Context, store db.Store, emails []string) ([]string, error) {
+	var missing []string
+	err := store.ExecTx(ctx, func(q db.Querier) error {
+		role, err := q.GetRoleByName(ctx, RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		for _, email := range emails {
+			user, err := q.GetUserByEmail(ctx, email)
+			if errors.Is(err, pgx.ErrNoRows) {
+				missing = append(missing, email)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := q.AssignRole(ctx, db.AssignRoleParams{UserID: user.ID, RoleID: role.ID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return missing, err
+}
+
+// bootstrapAdmins grants RoleAdmin to the users of cfg.AUTH.ADMIN_EMAILS on
+// start, nobody holds it in a new deployment otherwise
+func bootstrapAdmins(lc fx.Lifecycle, cfg *config.Config, store db.Store, logger *zap.SugaredLogger) {
+	emails := cfg.AUTH.ADMIN_EMAILS
+	if len(emails) == 0 {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			missing, err := GrantAdmins(ctx, store, emails)
+			if err != nil {
+				return err
+			}
+			for _, email := range missing {
+				logger.Warnw("no user registered with the admin email, register it and restart", "email", email)
+			}
+			return nil
+		},
+	})
+}
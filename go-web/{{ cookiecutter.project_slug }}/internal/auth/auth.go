@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"exampleproj/config"
+	"exampleproj/db"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+)
+
+const (
+	refreshKeyTpl = "auth:refresh:%s"
+	revokedKeyTpl = "auth:revoked:%s"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Module provides the Authenticator and the Policy, and grants the admin
+// role to cfg.AUTH.ADMIN_EMAILS
+var Module = fx.Module("auth",
+	fx.Provide(NewAuthenticator),
+	fx.Provide(NewPolicy),
+	fx.Invoke(bootstrapAdmins),
+)
+
+// User is the authenticated user stored in the request context
+type User struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+// Claims are the claims carried by the access token
+type Claims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is issued on login and on every refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// Authenticator issues signed access tokens and rotating refresh tokens.
+//
+// The refresh tokens are opaque random strings kept in redis, each of them
+// can be used once, refreshing returns a new pair and drops the old token.
+type Authenticator struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	rdb        *redis.Client
+}
+
+// NewAuthenticator refuses an empty secret, and the LocalAuthSecret default
+// outside of the local env, anyone could forge the tokens signed with it
+func NewAuthenticator(cfg *config.Config, rdb *redis.Client) (*Authenticator, error) {
+	switch {
+	case cfg.AUTH.SECRET == "":
+		return nil, errors.New("AUTH_SECRET is empty")
+	case cfg.AUTH.SECRET == config.LocalAuthSecret && cfg.App.Env != config.Local:
+		return nil, fmt.Errorf("AUTH_SECRET is the local default, set a secret for the %s env", cfg.App.Env)
+	}
+
+	return &Authenticator{
+		secret:     []byte(cfg.AUTH.SECRET),
+		issuer:     cfg.AUTH.ISSUER,
+		accessTTL:  cfg.AUTH.ACCESS_TOKEN_TTL,
+		refreshTTL: cfg.AUTH.REFRESH_TOKEN_TTL,
+		rdb:        rdb,
+	}, nil
+}
+
+// Issue creates a new token pair for the user
+func (a *Authenticator) Issue(ctx context.Context, user User) (TokenPair, error) {
+	now := time.Now()
+	jti, err := randomToken(16)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	claims := Claims{
+		Email: user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    a.issuer,
+			Subject:   strconv.Itoa(int(user.ID)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.accessTTL)),
+		},
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.secret)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := a.rdb.Set(ctx, refreshKey(refreshToken), payload, a.refreshTTL).Err(); err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    a.accessTTL,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair, the given token is
+// consumed so replaying it fails. The user is loaded again, the tokens of
+// the deleted users are invalid.
+func (a *Authenticator) Refresh(ctx context.Context, q db.Querier, refreshToken string) (TokenPair, error) {
+	payload, err := a.rdb.GetDel(ctx, refreshKey(refreshToken)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return TokenPair{}, ErrInvalidToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	var user User
+	if err := json.Unmarshal(payload, &user); err != nil {
+		return TokenPair{}, err
+	}
+
+	current, err := q.GetUser(ctx, user.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return TokenPair{}, ErrInvalidToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return a.Issue(ctx, User{ID: current.ID, Email: current.Email})
+}
+
+// Revoke drops the refresh token and denies the access token until it expires
+func (a *Authenticator) Revoke(ctx context.Context, claims *Claims, refreshToken string) error {
+	if refreshToken != "" {
+		if err := a.rdb.Del(ctx, refreshKey(refreshToken)).Err(); err != nil {
+			return err
+		}
+	}
+
+	if claims == nil || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return a.rdb.Set(ctx, fmt.Sprintf(revokedKeyTpl, claims.ID), 1, ttl).Err()
+}
+
+// Verify parses and validates the access token
+func (a *Authenticator) Verify(ctx context.Context, accessToken string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return a.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(a.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	revoked, err := a.rdb.Exists(ctx, fmt.Sprintf(revokedKeyTpl, claims.ID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if revoked > 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// UserFromClaims returns the user the claims were issued for
+func UserFromClaims(claims *Claims) (User, error) {
+	id, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil {
+		return User{}, ErrInvalidToken
+	}
+	return User{ID: int32(id), Email: claims.Email}, nil
+}
+
+// refreshKey stores the hash of the refresh token so a leaked redis dump
+// does not leak usable tokens
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf(refreshKeyTpl, hex.EncodeToString(sum[:]))
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"exampleproj/internal/app"
+)
+
+type ctxKey int
+
+const (
+	userKey ctxKey = iota
+	claimsKey
+)
+
+// Middleware populates the current user into the request context when the
+// request carries a valid bearer token. Anonymous requests pass through, an
+// invalid token is rejected with a 401.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			app.RenderError(w, r, app.NewMyError(ErrInvalidToken, app.ErrorCodeInvalidToken))
+			return
+		}
+
+		claims, err := a.Verify(r.Context(), token)
+		if errors.Is(err, ErrInvalidToken) {
+			app.RenderError(w, r, app.NewMyError(err, app.ErrorCodeInvalidToken))
+			return
+		}
+		if err != nil {
+			app.RenderError(w, r, err)
+			return
+		}
+
+		user, err := UserFromClaims(claims)
+		if err != nil {
+			app.RenderError(w, r, app.NewMyError(err, app.ErrorCodeInvalidToken))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), claimsKey, claims)
+		ctx = context.WithValue(ctx, userKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireUser protects the routes it's attached to, anonymous requests get
+// a 401. It relies on Authenticator.Middleware installed by the router.
+//
+//	r.With(auth.RequireUser).Delete("/users/{id}", h)
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFromContext(r.Context()); !ok {
+			app.RenderError(w, r, app.NewMyError(errors.New("authentication required"), app.ErrorCodeUnauthenticated))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserFromContext returns the authenticated user of the request
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey).(User)
+	return user, ok
+}
+
+// ClaimsFromContext returns the claims of the access token of the request
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"exampleproj/db"
+	"exampleproj/internal/app"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Permissions granted through the roles, they are named after the resource
+// and the action. The seed of the admin role has to be updated when adding
+// new ones.
+const (
+	PermissionUsersUpdate = "users:update"
+	PermissionUsersDelete = "users:delete"
+
+	PermissionAuthorsUpdate = "authors:update"
+	PermissionAuthorsDelete = "authors:delete"
+)
+
+var ErrForbidden = errors.New("permission denied")
+
+// Policy checks the permissions the roles of the current user grant
+type Policy struct {
+	store db.Store
+}
+
+func NewPolicy(store db.Store) *Policy {
+	return &Policy{store: store}
+}
+
+// Allowed reports whether the roles of the user grant the permission
+func (p *Policy) Allowed(ctx context.Context, user User, permission string) (bool, error) {
+	return allowed(ctx, p.store, user, permission)
+}
+
+func allowed(ctx context.Context, q db.Querier, user User, permission string) (bool, error) {
+	permissions, err := q.ListUserPermissions(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// AllowOwnerOr lets the owner of a resource through, the other users need
+// the permission. It's meant for the refiners loading the resource, the
+// route only knows its id so the guards can't tell the owner. The
+// permissions are read with q, the querier of the refiner transaction.
+func (p *Policy) AllowOwnerOr(ctx context.Context, q db.Querier, ownerID int32, permission string) error {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return app.NewMyError(errors.New("authentication required"), app.ErrorCodeUnauthenticated)
+	}
+
+	if user.ID == ownerID {
+		return nil
+	}
+
+	ok, err := allowed(ctx, q, user, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return app.NewMyError(ErrForbidden, app.ErrorCodeForbidden)
+	}
+	return nil
+}
+
+// Require restricts the route to the users granted the permission,
+// anonymous requests get a 401 and the others a 403.
+//
+//	r.With(u.policy.Require(auth.PermissionUsersDelete)).Delete("/users/{id}", h)
+func (p *Policy) Require(permission string) func(http.Handler) http.Handler {
+	return p.require(permission, "")
+}
+
+// RequireSelfOr is like Require but also lets the user addressed by the url
+// param through, ex. a user can update its own profile.
+func (p *Policy) RequireSelfOr(param string, permission string) func(http.Handler) http.Handler {
+	return p.require(permission, param)
+}
+
+func (p *Policy) require(permission string, selfParam string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return RequireUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _ := UserFromContext(r.Context())
+
+			if selfParam != "" && chi.URLParam(r, selfParam) == strconv.Itoa(int(user.ID)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, err := p.Allowed(r.Context(), user, permission)
+			if err != nil {
+				app.RenderError(w, r, err)
+				return
+			}
+
+			if !allowed {
+				app.RenderError(w, r, app.NewMyError(ErrForbidden, app.ErrorCodeForbidden))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
@@ -0,0 +1,197 @@
+// Package health reports whether the instance can serve traffic.
+//
+// The dependencies register a Check in the "health_checks" group with
+// AsCheck, the Registry runs them for the readiness probe. The liveness
+// probe runs none of them, a restart doesn't fix a database outage.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exampleproj/config"
+
+	"go.uber.org/fx"
+)
+
+// Status of a check or of a report
+type Status string
+
+const (
+	StatusOK           Status = "ok"
+	StatusFail         Status = "fail"
+	StatusShuttingDown Status = "shutting_down"
+)
+
+// Module provides the Registry of the checks in the "health_checks" group
+var Module = fx.Module("health",
+	fx.Provide(
+		fx.Annotate(
+			NewRegistry,
+			fx.ParamTags(``, `group:"health_checks"`),
+		),
+	),
+)
+
+// AsCheck annotates f, a constructor of a Check, to register its check in
+// the Registry
+func AsCheck(f any) any {
+	return fx.Annotate(
+		f,
+		fx.ResultTags(`group:"health_checks"`),
+	)
+}
+
+// Check probes a dependency of the instance
+type Check struct {
+	// Name identifies the check in the reports
+	Name string
+	// Timeout bounds Probe, cfg.HEALTH.TIMEOUT when zero
+	Timeout time.Duration
+	// Optional checks are reported but don't fail the readiness, the
+	// instance keeps serving without their dependency
+	Optional bool
+	Probe    func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a Check
+type CheckResult struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of the checks
+type Report struct {
+	Status    Status                 `json:"status"`
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// OK tells the report passed
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Registry runs the checks for the probes, the readiness results are cached
+// for cfg.HEALTH.CACHE_TTL so the probes don't hammer the dependencies.
+type Registry struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	shuttingDown atomic.Bool
+
+	mu     sync.Mutex
+	cached Report
+}
+
+func NewRegistry(cfg *config.Config, checks []Check) *Registry {
+	return &Registry{
+		checks:   checks,
+		timeout:  cfg.HEALTH.TIMEOUT,
+		cacheTTL: cfg.HEALTH.CACHE_TTL,
+	}
+}
+
+// Shutdown fails the readiness from now on, it's called once the instance
+// starts to shut down so the traffic is routed elsewhere while the pending
+// requests drain
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Liveness reports the process is up and serving, even while shutting down
+func (r *Registry) Liveness() Report {
+	return Report{Status: StatusOK, CheckedAt: time.Now()}
+}
+
+// Readiness runs the checks, or returns their cached results, and fails
+// when a required one fails or the instance is shutting down
+func (r *Registry) Readiness(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown, CheckedAt: time.Now()}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.cached.CheckedAt.IsZero() && time.Since(r.cached.CheckedAt) < r.cacheTTL {
+		return r.cached
+	}
+
+	// the results are shared by the probes, a probe going away mustn't fail
+	// the checks of the others
+	r.cached = r.run(context.WithoutCancel(ctx))
+	return r.cached
+}
+
+// run probes the checks concurrently
+func (r *Registry) run(ctx context.Context) Report {
+	results := make([]CheckResult, len(r.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = r.probe(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]CheckResult, len(r.checks)),
+	}
+	for i, check := range r.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK && !check.Optional {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// probe runs check within its timeout, a probe ignoring its context is
+// abandoned once the timeout is hit
+func (r *Registry) probe(ctx context.Context, check Check) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Probe(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("timed out after " + timeout.String())
+	}
+
+	result := CheckResult{
+		Status:   StatusOK,
+		Optional: check.Optional,
+		Duration: time.Since(start).Round(time.Microsecond).String(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
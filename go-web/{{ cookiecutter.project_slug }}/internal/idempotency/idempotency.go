@@ -0,0 +1,283 @@
+// Package idempotency makes the POST requests safe to retry.
+//
+// A client sends a unique Idempotency-Key header with its request, the
+// first request with a key runs and its response is kept in redis. The
+// retries with the same key and the same request replay that response
+// instead of running again, a different request reusing the key is
+// rejected with a 409.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderKey is the request header carrying the idempotency key
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed flags the replayed responses
+	HeaderReplayed = "Idempotent-Replayed"
+
+	recordKeyTpl = "idempotency:%s:%s"
+	maxKeyLength = 255
+)
+
+// Module provides the Guard of cfg.IDEMPOTENCY
+var Module = fx.Module("idempotency",
+	fx.Provide(NewGuard),
+)
+
+// record is the state of a key kept in redis
+type record struct {
+	// Fingerprint identifies the request which took the key
+	Fingerprint string `json:"fingerprint"`
+	// Status is 0 while the request runs
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Guard replays the responses of the POST requests retried with the same
+// Idempotency-Key. The keys are scoped to the current user, or to the ip of
+// the anonymous requests, it relies on auth.Authenticator.Middleware and
+// routers.RealIP running before it.
+type Guard struct {
+	rdb          *redis.Client
+	logger       *zap.SugaredLogger
+	ttl          time.Duration
+	lockTTL      time.Duration
+	maxBodyBytes int64
+	// prefixes are the routes the keys are honored on
+	prefixes []string
+}
+
+// NewGuard returns the guard of cfg.IDEMPOTENCY, nil when it's disabled
+func NewGuard(cfg *config.Config, rdb *redis.Client, logger *zap.SugaredLogger) (*Guard, error) {
+	conf := cfg.IDEMPOTENCY
+	if !conf.ENABLED {
+		return nil, nil
+	}
+
+	prefixes := make([]string, 0, len(conf.ROUTES))
+	for _, prefix := range conf.ROUTES {
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid idempotency route %q, want /prefix", prefix)
+		}
+		prefixes = append(prefixes, strings.TrimSuffix(prefix, "/"))
+	}
+
+	return &Guard{
+		rdb:          rdb,
+		logger:       logger,
+		ttl:          conf.TTL,
+		lockTTL:      conf.LOCK_TTL,
+		maxBodyBytes: conf.MAX_BODY_BYTES,
+		prefixes:     prefixes,
+	}, nil
+}
+
+// guards reports whether the keys are honored on path
+func (g *Guard) guards(path string) bool {
+	for _, prefix := range g.prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware runs the first request of a key and records its response, the
+// retries get the recorded response. The requests without the header, the
+// other methods and the routes out of cfg.IDEMPOTENCY.ROUTES pass through.
+//
+// The 5xx responses aren't recorded, the key is released so the request
+// can be retried.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if r.Method != http.MethodPost || key == "" || !g.guards(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			app.RenderError(w, r, app.NewMyErrorf(app.ErrorCodeInvalidParam, HeaderKey))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, g.maxBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			app.RenderError(w, r, app.NewMyErrorWithHTTPCode(err, app.ErrorCodeInvalidBody, http.StatusRequestEntityTooLarge))
+			return
+		}
+		if err != nil {
+			app.RenderError(w, r, app.NewMyError(err, app.ErrorCodeInvalidBody))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		recordKey := fmt.Sprintf(recordKeyTpl, scope(r), key)
+		fp := fingerprint(r, body)
+
+		pending, err := json.Marshal(record{Fingerprint: fp})
+		if err != nil {
+			app.RenderError(w, r, err)
+			return
+		}
+
+		acquired, err := g.rdb.SetNX(r.Context(), recordKey, pending, g.lockTTL).Result()
+		if err != nil {
+			app.RenderError(w, r, app.NewMyError(err, app.ErrorCodeUnavailable))
+			return
+		}
+
+		if !acquired {
+			g.replay(w, r, recordKey, fp)
+			return
+		}
+		g.record(w, r, next, recordKey, fp)
+	})
+}
+
+// replay answers the retry with the recorded response
+func (g *Guard) replay(w http.ResponseWriter, r *http.Request, recordKey, fp string) {
+	payload, err := g.rdb.Get(r.Context(), recordKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// released since, the client may retry
+		app.RenderError(w, r, app.NewMyError(errors.New("idempotency key released"), app.ErrorCodeIdempotencyInProgress))
+		return
+	}
+	if err != nil {
+		app.RenderError(w, r, app.NewMyError(err, app.ErrorCodeUnavailable))
+		return
+	}
+
+	var rec record
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		app.RenderError(w, r, err)
+		return
+	}
+
+	if rec.Fingerprint != fp {
+		app.RenderError(w, r, app.NewMyError(errors.New("request mismatch"), app.ErrorCodeIdempotencyKeyReused))
+		return
+	}
+	if rec.Status == 0 {
+		app.RenderError(w, r, app.NewMyError(errors.New("request in progress"), app.ErrorCodeIdempotencyInProgress))
+		return
+	}
+
+	h := w.Header()
+	for name, values := range rec.Header {
+		h[name] = values
+	}
+	h.Set(HeaderReplayed, "true")
+	w.WriteHeader(rec.Status)
+	w.Write(rec.Body)
+}
+
+// record runs the request and keeps its response for the retries
+func (g *Guard) record(w http.ResponseWriter, r *http.Request, next http.Handler, recordKey, fp string) {
+	// the response is recorded even when the client is gone
+	ctx := context.WithoutCancel(r.Context())
+
+	recorded := false
+	defer func() {
+		if !recorded {
+			// the retries run the request again
+			if err := g.rdb.Del(ctx, recordKey).Err(); err != nil {
+				g.logger.Warnw("failed to release the idempotency key", "key", recordKey, "error", err)
+			}
+		}
+	}()
+
+	before := w.Header().Clone()
+	var body bytes.Buffer
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	ww.Tee(&body)
+
+	next.ServeHTTP(ww, r)
+
+	status := ww.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusInternalServerError {
+		return
+	}
+
+	payload, err := json.Marshal(record{
+		Fingerprint: fp,
+		Status:      status,
+		Header:      handlerHeader(before, w.Header()),
+		Body:        body.Bytes(),
+	})
+	if err == nil {
+		err = g.rdb.Set(ctx, recordKey, payload, g.ttl).Err()
+	}
+	if err != nil {
+		g.logger.Warnw("failed to record the idempotent response", "key", recordKey, "error", err)
+		return
+	}
+	recorded = true
+}
+
+// encodingHeaders describe the response as sent by the compressor, which
+// runs outside of the guard, the recorded body isn't encoded
+var encodingHeaders = []string{"Content-Encoding", "Content-Length", "Vary"}
+
+// handlerHeader is the header set by the handler, the ones of the outer
+// middlewares, ex. the rate limits, are set again on the retries
+func handlerHeader(before, after http.Header) http.Header {
+	header := http.Header{}
+	for name, values := range after {
+		if !slices.Equal(before[name], values) && !slices.Contains(encodingHeaders, name) {
+			header[name] = values
+		}
+	}
+	return header
+}
+
+// scope isolates the keys of the users, the anonymous requests are scoped to
+// their ip
+func scope(r *http.Request) string {
+	if user, ok := auth.UserFromContext(r.Context()); ok {
+		return "user:" + strconv.Itoa(int(user.ID))
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// routers.RealIP sets the address without a port
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+// fingerprint identifies the request sent with a key
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
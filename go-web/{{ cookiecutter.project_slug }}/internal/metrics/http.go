@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// notFound labels the requests matching no route, the raw paths would blow
+// up the cardinality
+const notFound = "not_found"
+
+// HTTP counts the requests per chi route pattern, ex. /users/{id}
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+func NewHTTP() *HTTP {
+	return &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "The http requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "The latency of the http requests by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "The http requests being served.",
+		}),
+	}
+}
+
+// Middleware observes the requests, it has to be mounted on the root router
+// so the route pattern is complete once the request is served
+func (h *HTTP) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.inFlight.Inc()
+		defer h.inFlight.Dec()
+
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := notFound
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		h.requests.WithLabelValues(route, r.Method, strconv.Itoa(status)).Inc()
+		h.duration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+func (h *HTTP) Describe(ch chan<- *prometheus.Desc) {
+	h.requests.Describe(ch)
+	h.duration.Describe(ch)
+	h.inFlight.Describe(ch)
+}
+
+func (h *HTTP) Collect(ch chan<- prometheus.Metric) {
+	h.requests.Collect(ch)
+	h.duration.Collect(ch)
+	h.inFlight.Collect(ch)
+}
+
+var _ prometheus.Collector = (*HTTP)(nil)
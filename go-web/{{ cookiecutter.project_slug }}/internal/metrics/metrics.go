@@ -0,0 +1,65 @@
+// Package metrics collects the prometheus metrics of the apps.
+//
+// Every module contributes its own collectors to the "metrics_collectors"
+// group, with AsCollector for the constructors of a collector or Expose for
+// the components also used for something else. The Registry of the Module
+// gathers them with the go runtime and process metrics, the admin server
+// serves it on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/fx"
+)
+
+// Namespace prefixes the metrics of the apps
+const Namespace = "exampleproj"
+
+// Module provides the Registry of the collectors in the
+// "metrics_collectors" group, as a prometheus.Gatherer too
+var Module = fx.Module("metrics",
+	fx.Provide(
+		fx.Annotate(
+			NewRegistry,
+			fx.ParamTags(`group:"metrics_collectors"`),
+		),
+		func(registry *prometheus.Registry) prometheus.Gatherer {
+			return registry
+		},
+	),
+)
+
+// AsCollector annotates f, a constructor of a prometheus.Collector, to
+// register its collector in the Registry
+func AsCollector(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(prometheus.Collector)),
+		fx.ResultTags(`group:"metrics_collectors"`),
+	)
+}
+
+// Expose registers the T provided to the app, which is a collector too, in
+// the Registry
+func Expose[T prometheus.Collector]() any {
+	return AsCollector(func(c T) T {
+		return c
+	})
+}
+
+// NewRegistry registers the collectors with the go runtime and process ones
+func NewRegistry(cs []prometheus.Collector) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+
+	cs = append(cs,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
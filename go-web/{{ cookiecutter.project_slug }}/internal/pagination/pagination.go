@@ -0,0 +1,241 @@
+// Package pagination is the keyset pagination of the list endpoints.
+//
+// A page is fetched after the sort value and the id of the last item of the
+// previous page instead of an offset, so the pages stay consistent while
+// rows are inserted and a deep page costs the same as the first one. The
+// position travels as an opaque cursor bound to the sort it was issued for.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ParamError reports the query param which can't be parsed
+type ParamError struct {
+	Param string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Param, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Sort is the order of a page, Field is one of the Spec sorts
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String is the query form of the sort, ex. -name for the descending names
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Cursor is the position of the last item of a page
+type Cursor struct {
+	// Sort is the sort the cursor was issued for
+	Sort string `json:"s"`
+	// Value is the sort value of the item, empty when sorting by id
+	Value string `json:"v,omitempty"`
+	ID    int32  `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor, it is url safe
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Spec declares how a list endpoint can be paginated
+type Spec struct {
+	// Sorts are the sortable fields, the first one is the default
+	Sorts []string
+	// Filters are the fields filtered by the query param of the same name
+	Filters []string
+}
+
+// Params are the pagination params of a request
+type Params struct {
+	Limit int
+	Sort  Sort
+	// After is the cursor of the previous page, nil for the first page
+	After   *Cursor
+	Filters map[string]string
+}
+
+// Params validates the params of a request, a nil value takes its default.
+// Only the filters of the spec are kept.
+func (s Spec) Params(limit *int, cursor *string, sort *string, filters map[string]string) (Params, error) {
+	params := Params{
+		Limit:   DefaultLimit,
+		Sort:    Sort{Field: s.Sorts[0]},
+		Filters: map[string]string{},
+	}
+
+	if limit != nil {
+		if *limit < 1 || *limit > MaxLimit {
+			return params, &ParamError{"limit", ErrInvalidLimit}
+		}
+		params.Limit = *limit
+	}
+
+	if sort != nil && *sort != "" {
+		field, desc := strings.CutPrefix(*sort, "-")
+		if !slices.Contains(s.Sorts, field) {
+			return params, &ParamError{"sort", ErrInvalidSort}
+		}
+		params.Sort = Sort{Field: field, Desc: desc}
+	}
+
+	if cursor != nil && *cursor != "" {
+		after, err := decodeCursor(*cursor)
+		// a cursor of another sort points nowhere
+		if err != nil || after.Sort != params.Sort.String() {
+			return params, &ParamError{"cursor", ErrInvalidCursor}
+		}
+		params.After = &after
+	}
+
+	for _, field := range s.Filters {
+		if value, ok := filters[field]; ok {
+			params.Filters[field] = value
+		}
+	}
+
+	return params, nil
+}
+
+// ParseQuery is Params for the routes outside the swagger spec
+func (s Spec) ParseQuery(query url.Values) (Params, error) {
+	var limit *int
+	if query.Has("limit") {
+		n, err := strconv.Atoi(query.Get("limit"))
+		if err != nil {
+			return Params{}, &ParamError{"limit", ErrInvalidLimit}
+		}
+		limit = &n
+	}
+
+	filters := map[string]string{}
+	for _, field := range s.Filters {
+		if query.Has(field) {
+			filters[field] = query.Get(field)
+		}
+	}
+
+	cursor, sort := query.Get("cursor"), query.Get("sort")
+	return s.Params(limit, &cursor, &sort, filters)
+}
+
+// FetchLimit is the number of rows to query, one more than the limit tells
+// whether there is a next page
+func (p Params) FetchLimit() int64 {
+	return int64(p.Limit) + 1
+}
+
+// AfterID is the id of the cursor, 0 for the first page
+func (p Params) AfterID() int32 {
+	if p.After == nil {
+		return 0
+	}
+	return p.After.ID
+}
+
+// AfterValue is the sort value of the cursor, empty for the first page
+func (p Params) AfterValue() string {
+	if p.After == nil {
+		return ""
+	}
+	return p.After.Value
+}
+
+// Query is the query string of the params
+func (p Params) Query() url.Values {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(p.Limit))
+	query.Set("sort", p.Sort.String())
+	if p.After != nil {
+		query.Set("cursor", p.After.Encode())
+	}
+	for field, value := range p.Filters {
+		query.Set(field, value)
+	}
+	return query
+}
+
+// Page is a page of items
+type Page[T any] struct {
+	Items []T
+	// Next are the params of the next page, nil on the last page
+	Next *Params
+}
+
+// NewPage trims the rows fetched with FetchLimit to the page, key returns
+// the sort value and the id of an item
+func NewPage[T any](rows []T, params Params, key func(T) (string, int32)) Page[T] {
+	if len(rows) <= params.Limit {
+		return Page[T]{Items: rows}
+	}
+
+	items := rows[:params.Limit]
+	value, id := key(items[len(items)-1])
+
+	next := params
+	next.After = &Cursor{Sort: params.Sort.String(), Value: value, ID: id}
+	return Page[T]{Items: items, Next: &next}
+}
+
+// NextCursor is the encoded cursor of the next page, nil on the last page
+func (p Page[T]) NextCursor() *string {
+	if p.Next == nil {
+		return nil
+	}
+	cursor := p.Next.After.Encode()
+	return &cursor
+}
+
+// Link is the RFC 8288 Link header pointing to the next page of path,
+// empty on the last page
+func (p Page[T]) Link(path string) string {
+	if p.Next == nil {
+		return ""
+	}
+	return fmt.Sprintf(`<%s?%s>; rel="next"`, path, p.Next.Query().Encode())
+}
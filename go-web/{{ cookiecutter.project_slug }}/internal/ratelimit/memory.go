@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops the idle keys
+const sweepInterval = time.Minute
+
+type window struct {
+	hits []time.Time
+	size time.Duration
+}
+
+// MemoryStore counts the hits in the memory of the instance, it's meant for
+// a single instance and the tests
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: map[string]*window{}, lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &window{}
+		s.windows[key] = w
+	}
+	w.size = limit.Window
+	w.expire(now)
+
+	allowed := len(w.hits) < limit.Requests
+	if allowed {
+		w.hits = append(w.hits, now)
+	}
+
+	reset := limit.Window
+	if len(w.hits) > 0 {
+		reset = w.hits[0].Add(limit.Window).Sub(now)
+	}
+
+	return Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: limit.Requests - len(w.hits),
+		Reset:     reset,
+	}, nil
+}
+
+// expire drops the hits out of the window
+func (w *window) expire(now time.Time) {
+	i := 0
+	for i < len(w.hits) && !w.hits[i].After(now.Add(-w.size)) {
+		i++
+	}
+	w.hits = w.hits[i:]
+}
+
+// sweep drops the keys without hits in their window
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, w := range s.windows {
+		w.expire(now)
+		if len(w.hits) == 0 {
+			delete(s.windows, key)
+		}
+	}
+}
+
+var _ Store = (*MemoryStore)(nil)
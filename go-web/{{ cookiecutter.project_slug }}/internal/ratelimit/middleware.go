@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+
+	"go.uber.org/zap"
+)
+
+// rule is the limit of the paths under prefix, the default rule has no
+// prefix
+type rule struct {
+	prefix string
+	limit  Limit
+}
+
+// name identifies the rule in the store keys, the routes of a rule share
+// their limit
+func (r rule) name() string {
+	if r.prefix == "" {
+		return "default"
+	}
+	return r.prefix
+}
+
+// Limiter limits the requests of every client, the authenticated users by
+// id and the others by ip. It relies on auth.Authenticator.Middleware
+// running before it.
+type Limiter struct {
+	store  Store
+	logger *zap.SugaredLogger
+	// rules are sorted by the longest prefix first, the default is last
+	rules []rule
+}
+
+// NewLimiter returns the limiter of cfg.RATE_LIMIT, nil when it's disabled
+func NewLimiter(cfg *config.Config, store Store, logger *zap.SugaredLogger) (*Limiter, error) {
+	conf := cfg.RATE_LIMIT
+	if !conf.ENABLED {
+		return nil, nil
+	}
+
+	limit, err := ParseLimit(conf.LIMIT)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []rule{
+		{limit: limit},
+	}
+	for _, value := range conf.ROUTES {
+		prefix, s, ok := strings.Cut(value, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid route limit %q, want /prefix=requests/window", value)
+		}
+
+		limit, err := ParseLimit(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route limit %q: %w", value, err)
+		}
+		rules = append(rules, rule{strings.TrimSuffix(prefix, "/"), limit})
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].prefix) > len(rules[j].prefix)
+	})
+
+	return &Limiter{store: store, logger: logger, rules: rules}, nil
+}
+
+func (l *Limiter) ruleOf(path string) rule {
+	for _, r := range l.rules {
+		if r.prefix == "" || path == r.prefix || strings.HasPrefix(path, r.prefix+"/") {
+			return r
+		}
+	}
+	return rule{}
+}
+
+// Middleware counts the request against the limit of its route and answers
+// a 429 past it. The responses carry the RateLimit-* headers, the denied
+// ones a Retry-After too.
+//
+// A failing store lets the requests through, the api stays up without the
+// redis.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule := l.ruleOf(r.URL.Path)
+		if rule.limit.Disabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := fmt.Sprintf("ratelimit:%s:%s", rule.name(), client(r))
+		res, err := l.store.Allow(r.Context(), key, rule.limit)
+		if err != nil {
+			l.logger.Warnw("rate limit store failed, letting the request through", "key", key, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reset := seconds(res.Reset)
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(max(res.Remaining, 0)))
+		h.Set("RateLimit-Reset", reset)
+
+		if !res.Allowed {
+			h.Set("Retry-After", reset)
+			app.RenderError(w, r, app.NewMyErrorf(app.ErrorCodeRateLimited, reset))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// client identifies the user of the request, or its ip when anonymous
+func client(r *http.Request) string {
+	if user, ok := auth.UserFromContext(r.Context()); ok {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// routers.RealIP sets the address without a port
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+// seconds rounds d up to whole seconds, the unit of the headers
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
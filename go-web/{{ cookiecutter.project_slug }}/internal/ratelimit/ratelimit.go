@@ -0,0 +1,94 @@
+// Package ratelimit limits the requests of the clients over a sliding window.
+//
+// The hits are counted by a Store, redis so the instances of the api share
+// the limits, or memory for a single instance and the tests. The Limiter
+// middleware picks the limit of the route and answers a 429 past it.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"exampleproj/config"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+)
+
+// Module provides the Store and the Limiter of cfg.RATE_LIMIT
+var Module = fx.Module("ratelimit",
+	fx.Provide(NewStore),
+	fx.Provide(NewLimiter),
+)
+
+// Limit allows Requests per sliding Window, a zero Limit allows everything
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseLimit parses a limit written as requests/window, ex. 100/1m, a bare 0
+// disables the limit
+func ParseLimit(s string) (Limit, error) {
+	if s == "0" {
+		return Limit{}, nil
+	}
+
+	requests, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, want requests/window", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, want requests/window", s)
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, want requests/window", s)
+	}
+
+	return Limit{Requests: n, Window: d}, nil
+}
+
+// Disabled reports whether the limit lets every request through
+func (l Limit) Disabled() bool {
+	return l.Requests == 0
+}
+
+// Result is the outcome of a hit
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the oldest hit of the window expires, the
+	// client may retry after it when the hit was denied
+	Reset time.Duration
+}
+
+// Store counts the hits of the keys
+type Store interface {
+	// Allow records a hit of key unless the limit is reached
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// NewStore returns the store of cfg.RATE_LIMIT.STORE, the redis client is
+// only required by the redis store
+func NewStore(cfg *config.Config, rdb *redis.Client) (Store, error) {
+	switch cfg.RATE_LIMIT.STORE {
+	case "", "redis":
+		if rdb == nil {
+			return nil, errors.New("the redis rate limit store requires a redis client")
+		}
+		return NewRedisStore(rdb), nil
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RATE_LIMIT.STORE)
+	}
+}
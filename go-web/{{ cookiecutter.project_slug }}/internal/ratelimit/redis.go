@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow keeps the hits of a key in a sorted set scored by their
+// time in milliseconds, the hits older than the window are dropped before
+// counting. It returns {allowed, remaining, reset in milliseconds}.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local reset = window
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+// RedisStore counts the hits in redis, the instances sharing the redis
+// share the limits
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	// the hits of the same millisecond need distinct members
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Result{}, err
+	}
+
+	now := time.Now().UnixMilli()
+	res, err := slidingWindow.Run(ctx, s.rdb, []string{key},
+		now, limit.Window.Milliseconds(), limit.Requests, hex.EncodeToString(id),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:   res[0] == 1,
+		Limit:     limit.Requests,
+		Remaining: int(res[1]),
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
+
+var _ Store = (*RedisStore)(nil)
@@ -0,0 +1,175 @@
+// Package shutdown stops the apps in phases so no in-flight work is dropped.
+//
+// The components register their stop function on the Coordinator with the
+// Phase they belong to instead of an fx OnStop hook. On SIGINT or SIGTERM the
+// Coordinator runs the phases in order, the hooks of a phase concurrently:
+//
+//  1. PhaseDrain: the readiness fails, then DRAIN_DELAY lets the orchestrator
+//     route the traffic elsewhere
+//  2. PhaseHTTP: the servers stop accepting and the in-flight requests finish
+//  3. PhaseConnections: the long-lived connections, ex. the websockets, are
+//     closed
+//  4. PhaseWorkers: the background workers finish their running tasks
+//
+// The phases after the drain share the cfg.SHUTDOWN.TIMEOUT deadline.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"exampleproj/config"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// StopTimeout is the fx stop timeout of the apps, it bounds the whole
+// shutdown so SHUTDOWN_DRAIN_DELAY plus SHUTDOWN_TIMEOUT must stay below it
+const StopTimeout = 2 * time.Minute
+
+// Phase orders the shutdown hooks
+type Phase int
+
+const (
+	PhaseDrain Phase = iota
+	PhaseHTTP
+	PhaseConnections
+	PhaseWorkers
+)
+
+var phases = []Phase{PhaseDrain, PhaseHTTP, PhaseConnections, PhaseWorkers}
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseDrain:
+		return "drain"
+	case PhaseHTTP:
+		return "http"
+	case PhaseConnections:
+		return "connections"
+	case PhaseWorkers:
+		return "workers"
+	}
+	return "unknown"
+}
+
+// Module provides the Coordinator, the apps invoke Install last
+var Module = fx.Module("shutdown",
+	fx.Provide(NewCoordinator),
+)
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Coordinator runs the shutdown of the app in phases
+type Coordinator struct {
+	logger     *zap.SugaredLogger
+	drainDelay time.Duration
+	timeout    time.Duration
+
+	mu    sync.Mutex
+	hooks map[Phase][]hook
+	once  sync.Once
+	err   error
+}
+
+func NewCoordinator(cfg *config.Config, logger *zap.SugaredLogger) *Coordinator {
+	return &Coordinator{
+		logger:     logger,
+		drainDelay: cfg.SHUTDOWN.DRAIN_DELAY,
+		timeout:    cfg.SHUTDOWN.TIMEOUT,
+		hooks:      map[Phase][]hook{},
+	}
+}
+
+// Install runs the shutdown on the stop of the app. It has to be invoked
+// after the components are built, the OnStop hooks run in the reverse order
+// so the shutdown runs before the dependencies, ex. the database pool, are
+// closed.
+func Install(lc fx.Lifecycle, c *Coordinator) {
+	lc.Append(fx.StopHook(c.Shutdown))
+}
+
+// Register adds stop to phase, name identifies it in the logs. stop must
+// return once ctx is done.
+func (c *Coordinator) Register(phase Phase, name string, stop func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks[phase] = append(c.hooks[phase], hook{name, stop})
+}
+
+// Shutdown runs the phases once, the later calls return the error of the
+// first one
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		c.err = c.shutdown(ctx)
+	})
+	return c.err
+}
+
+func (c *Coordinator) shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	hooks := c.hooks
+	c.hooks = map[Phase][]hook{}
+	c.mu.Unlock()
+
+	start := time.Now()
+	c.logger.Infow("shutdown started", "drain_delay", c.drainDelay, "timeout", c.timeout)
+
+	var errs []error
+	errs = append(errs, c.runPhase(ctx, PhaseDrain, hooks[PhaseDrain]))
+	if len(hooks[PhaseDrain]) > 0 && c.drainDelay > 0 {
+		c.logger.Infow("waiting for the traffic to drain", "delay", c.drainDelay)
+		select {
+		case <-time.After(c.drainDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	for _, phase := range phases[1:] {
+		errs = append(errs, c.runPhase(ctx, phase, hooks[phase]))
+	}
+
+	err := errors.Join(errs...)
+	defer c.logger.Sync()
+	if err != nil {
+		c.logger.Errorw("shutdown completed with errors", "duration", time.Since(start), "error", err)
+		return err
+	}
+	c.logger.Infow("shutdown completed", "duration", time.Since(start))
+	return nil
+}
+
+// runPhase runs the hooks of phase concurrently
+func (c *Coordinator) runPhase(ctx context.Context, phase Phase, hooks []hook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	c.logger.Infow("shutdown phase started", "phase", phase, "hooks", len(hooks))
+
+	errs := make([]error, len(hooks))
+	var wg sync.WaitGroup
+	for i, h := range hooks {
+		wg.Add(1)
+		go func(i int, h hook) {
+			defer wg.Done()
+			if err := h.stop(ctx); err != nil {
+				c.logger.Errorw("shutdown hook failed", "phase", phase, "hook", h.name, "error", err)
+				errs[i] = err
+			}
+		}(i, h)
+	}
+	wg.Wait()
+
+	c.logger.Infow("shutdown phase completed", "phase", phase, "duration", time.Since(start))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/health"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/fx"
+)
+
+// NewInspector returns an inspector of the queues, closed with the app
+func NewInspector(lc fx.Lifecycle, cfg *config.Config) *asynq.Inspector {
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{
+		Addr: fmt.Sprintf("%s:%s", cfg.REDIS.ADDR, cfg.REDIS.PORT),
+	})
+	lc.Append(fx.StopHook(inspector.Close))
+	return inspector
+}
+
+// NewHealthCheck reports whether a worker is processing the tasks. It's
+// optional, the api keeps serving while the tasks queue up.
+func NewHealthCheck(inspector *asynq.Inspector) health.Check {
+	return health.Check{
+		Name:     "asynq",
+		Optional: true,
+		// the inspector takes no context, the registry abandons it past the
+		// timeout
+		Probe: func(ctx context.Context) error {
+			servers, err := inspector.Servers()
+			if err != nil {
+				return err
+			}
+			if len(servers) == 0 {
+				return errors.New("no worker running")
+			}
+			return nil
+		},
+	}
+}
+
+// NewPythHealthCheck reports whether the Pyth api feeding the prices is up.
+// It's optional, the stored prices are still served without it.
+func NewPythHealthCheck(cfg *config.Config) health.Check {
+	client := app.NewPythAPIClient(cfg.WEB3.PYTH_API_HOST)
+	return health.Check{
+		Name:     "pyth",
+		Optional: true,
+		Probe:    client.Live,
+	}
+}
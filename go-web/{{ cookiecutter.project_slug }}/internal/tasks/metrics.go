@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"exampleproj/internal/metrics"
+
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueueCollector exports the depth of the queues on every scrape
+type QueueCollector struct {
+	inspector *asynq.Inspector
+
+	up        *prometheus.Desc
+	tasks     *prometheus.Desc
+	latency   *prometheus.Desc
+	processed *prometheus.Desc
+	failed    *prometheus.Desc
+}
+
+func NewQueueCollector(inspector *asynq.Inspector) *QueueCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "asynq", name), help, labels, nil)
+	}
+
+	return &QueueCollector{
+		inspector: inspector,
+		up:        desc("up", "Whether the queues could be inspected."),
+		tasks:     desc("queue_tasks", "The tasks of the queues by state.", "queue", "state"),
+		latency:   desc("queue_latency_seconds", "The age of the oldest pending task of the queues.", "queue"),
+		processed: desc("queue_processed_total", "The tasks processed from the queues, failed included.", "queue"),
+		failed:    desc("queue_failed_total", "The tasks of the queues which failed.", "queue"),
+	}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.tasks
+	ch <- c.latency
+	ch <- c.processed
+	ch <- c.failed
+}
+
+// Collect reports asynq_up 0 when redis fails, the other metrics of the
+// scrape are kept
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := c.inspector.Queues()
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	up := 1.0
+	for _, queue := range queues {
+		info, err := c.inspector.GetQueueInfo(queue)
+		if err != nil {
+			up = 0
+			continue
+		}
+
+		states := map[string]int{
+			"pending":     info.Pending,
+			"active":      info.Active,
+			"scheduled":   info.Scheduled,
+			"retry":       info.Retry,
+			"archived":    info.Archived,
+			"completed":   info.Completed,
+			"aggregating": info.Aggregating,
+		}
+		for state, n := range states {
+			ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(n), queue, state)
+		}
+		ch <- prometheus.MustNewConstMetric(c.latency, prometheus.GaugeValue, info.Latency.Seconds(), queue)
+		ch <- prometheus.MustNewConstMetric(c.processed, prometheus.CounterValue, float64(info.ProcessedTotal), queue)
+		ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(info.FailedTotal), queue)
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up)
+}
+
+// TaskMetrics observes the tasks processed by the worker
+type TaskMetrics struct {
+	tasks    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewTaskMetrics() *TaskMetrics {
+	return &TaskMetrics{
+		tasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "asynq",
+			Name:      "tasks_total",
+			Help:      "The tasks processed by type and outcome, succeeded or failed.",
+		}, []string{"type", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "asynq",
+			Name:      "task_duration_seconds",
+			Help:      "The processing time of the tasks by type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+	}
+}
+
+// Middleware observes the tasks of the asynq.ServeMux
+func (m *TaskMetrics) Middleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		start := time.Now()
+		err := next.ProcessTask(ctx, t)
+
+		outcome := "succeeded"
+		if err != nil {
+			outcome = "failed"
+		}
+		m.tasks.WithLabelValues(t.Type(), outcome).Inc()
+		m.duration.WithLabelValues(t.Type()).Observe(time.Since(start).Seconds())
+		return err
+	})
+}
+
+func (m *TaskMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.tasks.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *TaskMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.tasks.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+var (
+	_ prometheus.Collector = (*QueueCollector)(nil)
+	_ prometheus.Collector = (*TaskMetrics)(nil)
+)
@@ -1,34 +1,88 @@
 package main
 
 import (
+	_ "embed"
 	"net/http"
 
+	"exampleproj/cache"
 	"exampleproj/config"
 	"exampleproj/db"
+	"exampleproj/internal/admin"
 	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/health"
+	"exampleproj/internal/idempotency"
+	"exampleproj/internal/metrics"
+	"exampleproj/internal/ratelimit"
+	"exampleproj/internal/shutdown"
+	"exampleproj/internal/tasks"
 	"exampleproj/routers"
 	"exampleproj/routers/handlers"
 
 	"go.uber.org/fx"
 )
 
+var (
+	//go:embed swagger.yml
+	openAPIDocument []byte
+	//go:embed asyncapi.yaml
+	asyncAPIDocument []byte
+)
+
 func main() {
 	fx.New(
 		fx.Provide(
-			app.NewHTTPServer,
+			fx.Annotate(
+				app.NewHTTPServer,
+				fx.ParamTags(``, ``, ``, ``, ``, `optional:"true"`),
+			),
 			fx.Annotate(
 				routers.NewRouter,
-				fx.ParamTags(`group:"handlers"`),
+				fx.ParamTags(`group:"handlers"`, `optional:"true"`, `optional:"true"`, `optional:"true"`, `optional:"true"`),
 			),
 
+			// the operations of swagger.yml are served by the API, add the
+			// handlers implementing them here
+			handlers.NewUserHandler,
+			handlers.NewAuthorHandler,
+			handlers.NewAuthHandler,
+			routers.AsRoute(handlers.NewAPI),
+
 			// Register other routes here
-			routers.AsRoute(handlers.NewUserHandler),
+			routers.AsRoute(handlers.NewDocsHandler),
+			routers.AsRoute(handlers.NewHealthHandler),
+
+			// the dependencies checked by /readyz
+			health.AsCheck(db.NewHealthCheck),
+			health.AsCheck(cache.NewHealthCheck),
+			tasks.NewInspector,
+			health.AsCheck(tasks.NewHealthCheck),
+			health.AsCheck(tasks.NewPythHealthCheck),
+
+			// the collectors of the metrics served by the admin server
+			metrics.NewHTTP,
+			metrics.Expose[*metrics.HTTP](),
+			metrics.AsCollector(db.NewPoolCollector),
+			metrics.AsCollector(cache.NewMetrics),
+			metrics.AsCollector(tasks.NewQueueCollector),
+			metrics.AsCollector(app.NewPythCollector),
 		),
 
+		fx.Supply(handlers.Docs{OpenAPI: openAPIDocument, AsyncAPI: asyncAPIDocument}),
 		fx.Provide(config.NewConfig),
 		fx.Provide(app.NewLogger),
-		fx.Provide(db.NewPostgresqlDB),
+		db.Module,
+		auth.Module,
+		ratelimit.Module,
+		idempotency.Module,
+		health.Module,
+		fx.Provide(cache.NewRedis),
 		fx.Provide(config.NewViper),
-		fx.Invoke(func(*http.Server) {}),
+		shutdown.Module,
+		metrics.Module,
+		admin.Module,
+		fx.Invoke(func(*http.Server, *admin.Server) {}),
+		fx.Invoke(shutdown.Install),
+		fx.StopTimeout(shutdown.StopTimeout),
 	).Run()
 }
@@ -3,11 +3,16 @@ package routers
 import (
 	"net/http"
 
+	"exampleproj/config"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/idempotency"
+	"exampleproj/internal/metrics"
+	"exampleproj/internal/ratelimit"
 	"exampleproj/routers/handlers"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // AsRoute annotates the given function with the necessary group tag and types for
@@ -30,10 +35,31 @@ func Health(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func NewRouter(handlers []handlers.Handler) *chi.Mux {
+// NewRouter mounts the handlers behind the Middlewares, the authenticator is
+// optional and populates the current user of the requests carrying a bearer
+// token when provided. The optional limiter and idempotency guard run after
+// it so the users are limited and keep their idempotency keys by id. The
+// optional http metrics come first to observe every request.
+func NewRouter(handlers []handlers.Handler, authenticator *auth.Authenticator, limiter *ratelimit.Limiter, guard *idempotency.Guard, httpMetrics *metrics.HTTP, cfg *config.Config, logger *zap.SugaredLogger) (*chi.Mux, error) {
+	mws, err := Middlewares(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	r := chi.NewRouter()
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	if httpMetrics != nil {
+		r.Use(httpMetrics.Middleware)
+	}
+	r.Use(mws...)
+	if authenticator != nil {
+		r.Use(authenticator.Middleware)
+	}
+	if limiter != nil {
+		r.Use(limiter.Middleware)
+	}
+	if guard != nil {
+		r.Use(guard.Middleware)
+	}
 	r.Get("/health", Health)
 
 	for _, handler := range handlers {
@@ -45,5 +71,5 @@ func NewRouter(handlers []handlers.Handler) *chi.Mux {
 	// r.Post("/", h)
 	// })
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/routers/schemas"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// optionalBodies are the operations whose request body is optional, the
+// strict server decodes every body so an empty one is served as {}
+var optionalBodies = map[string]bool{
+	"POST /auth/logout": true,
+}
+
+// API serves the operations of swagger.yml through the generated strict
+// server, the resource handlers implement their share of
+// schemas.StrictServerInterface. An operation added to swagger.yml doesn't
+// compile until one of them implements it.
+type API struct {
+	*UserHandler
+	*AuthorHandler
+	*AuthHandler
+
+	// guards are the permission checks of the operations by operation id,
+	// on top of the bearerAuth security declared in swagger.yml
+	guards    map[string]func(http.Handler) http.Handler
+	validator *RequestValidator
+}
+
+func NewAPI(users *UserHandler, authors *AuthorHandler, authHandler *AuthHandler, policy *auth.Policy) (*API, error) {
+	validator, err := NewRequestValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	return &API{
+		UserHandler:   users,
+		AuthorHandler: authors,
+		AuthHandler:   authHandler,
+		guards: map[string]func(http.Handler) http.Handler{
+			"UpdateUser": policy.RequireSelfOr("id", auth.PermissionUsersUpdate),
+			"DeleteUser": policy.Require(auth.PermissionUsersDelete),
+		},
+		validator: validator,
+	}, nil
+}
+
+func (a *API) RegisterRoute(r *chi.Mux) {
+	schemas.HandlerWithOptions(a.server(), a.options(r))
+}
+
+func (a *API) server() schemas.ServerInterface {
+	return schemas.NewStrictHandlerWithOptions(a, []schemas.StrictMiddlewareFunc{a.guard}, schemas.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  renderBodyError,
+		ResponseErrorHandlerFunc: app.RenderError,
+	})
+}
+
+// options runs the middlewares of the operations in reverse order, the
+// anonymous requests are rejected before the request is validated against
+// the spec, then the empty optional bodies are filled for the strict server
+func (a *API) options(r chi.Router) schemas.ChiServerOptions {
+	return schemas.ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      []schemas.MiddlewareFunc{optionalBody, a.validator.Middleware, requireBearer},
+		ErrorHandlerFunc: renderParamError,
+	}
+}
+
+// guard runs the permission check of the operation, the operation isn't
+// called when the check rejects the request and renders the error itself
+func (a *API) guard(f schemas.StrictHandlerFunc, operationID string) schemas.StrictHandlerFunc {
+	check, ok := a.guards[operationID]
+	if !ok {
+		return f
+	}
+
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		var response interface{}
+		var err error
+
+		check(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response, err = f(r.Context(), w, r, request)
+		})).ServeHTTP(w, r)
+
+		return response, err
+	}
+}
+
+// requireBearer rejects the anonymous requests of the operations declaring
+// the bearerAuth security, the generated wrapper flags them with
+// schemas.BearerAuthScopes
+func requireBearer(next http.Handler) http.Handler {
+	authenticated := auth.RequireUser(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Value(schemas.BearerAuthScopes) != nil {
+			authenticated.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func optionalBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+		if r.ContentLength == 0 && optionalBodies[route] {
+			r.Body = io.NopCloser(strings.NewReader("{}"))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// renderParamError reports the path and query params the generated wrapper
+// fails to parse
+func renderParamError(w http.ResponseWriter, r *http.Request, err error) {
+	var formatErr *schemas.InvalidParamFormatError
+	var requiredErr *schemas.RequiredParamError
+
+	switch {
+	case errors.As(err, &formatErr):
+		err = app.NewMyErrorf(app.ErrorCodeInvalidParam, formatErr.ParamName)
+	case errors.As(err, &requiredErr):
+		err = app.NewMyErrorf(app.ErrorCodeInvalidParam, requiredErr.ParamName)
+	}
+	app.RenderError(w, r, err)
+}
+
+// renderBodyError reports the bodies the strict server fails to decode
+func renderBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	app.RenderError(w, r, app.DecodeError(err))
+}
+
+var _ schemas.StrictServerInterface = (*API)(nil)
+var _ Handler = (*API)(nil)
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"exampleproj/db"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/routers/schemas"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type LoginValidator struct{}
+
+// dummyHash is compared with the passwords of the unknown emails, so they
+// take the time of a bcrypt comparison too
+var dummyHash = sync.OnceValue(func() string {
+	hash, err := app.HashPassword("dummy password")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+func NewLoginValidator() Refiner[schemas.LoginRequestObject, auth.User] {
+	return &LoginValidator{}
+}
+
+// refine checks the credentials of the login request and returns the
+// matching auth.User. Unknown emails and wrong passwords get the same 401
+// so the endpoint can't be used to enumerate the registered emails.
+func (l *LoginValidator) refine(ctx context.Context, request schemas.LoginRequestObject, q db.Querier) (auth.User, error) {
+	schema := *request.Body
+
+	validate := app.Validator()
+	if err := validate.Struct(schema); err != nil {
+		return auth.User{}, err
+	}
+
+	invalid := app.NewMyError(errors.New("login failed"), app.ErrorCodeInvalidCredentials)
+
+	user, err := q.GetUserByEmail(ctx, schema.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		app.CheckPasswordHash(schema.Password, dummyHash())
+		return auth.User{}, invalid
+	}
+	if err != nil {
+		return auth.User{}, err
+	}
+
+	if !app.CheckPasswordHash(schema.Password, user.PasswordHash) {
+		return auth.User{}, invalid
+	}
+
+	return auth.User{ID: user.ID, Email: user.Email}, nil
+}
+
+type RefreshTokenValidator struct {
+	// Optional allows an empty body, logout revokes the access token anyway
+	Optional bool
+}
+
+func NewRefreshTokenValidator() Refiner[*schemas.RefreshTokenRequest, string] {
+	return &RefreshTokenValidator{}
+}
+
+// NewLogoutTokenValidator builds the RefreshTokenValidator of the logout,
+// its refresh token is optional
+func NewLogoutTokenValidator() Refiner[*schemas.RefreshTokenRequest, string] {
+	return &RefreshTokenValidator{Optional: true}
+}
+
+// refine returns the refresh token of the *schemas.RefreshTokenRequest body
+func (v *RefreshTokenValidator) refine(ctx context.Context, schema *schemas.RefreshTokenRequest, q db.Querier) (string, error) {
+	if v.Optional && (schema == nil || schema.RefreshToken == "") {
+		return "", nil
+	}
+
+	validate := app.Validator()
+	if err := validate.Struct(schema); err != nil {
+		return "", err
+	}
+
+	return schema.RefreshToken, nil
+}
+
+// TokenComposer renders an auth.TokenPair as schemas.TokenResponse
+type TokenComposer struct {
+	auth.TokenPair
+}
+
+func NewTokenComposer(pair auth.TokenPair) Composer[schemas.TokenResponse] {
+	return &TokenComposer{pair}
+}
+
+func (t *TokenComposer) compose(ctx context.Context, q db.Querier) (schemas.TokenResponse, error) {
+	return schemas.TokenResponse{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(t.ExpiresIn.Seconds()),
+	}, nil
+}
+
+// AuthHandler serves the login, refresh and logout operations of
+// schemas.StrictServerInterface, the API serves them.
+type AuthHandler struct {
+	store  db.Store
+	logger *zap.SugaredLogger
+	auth   *auth.Authenticator
+}
+
+func NewAuthHandler(store db.Store, logger *zap.SugaredLogger, authenticator *auth.Authenticator) *AuthHandler {
+	return &AuthHandler{
+		store:  store,
+		logger: logger,
+		auth:   authenticator,
+	}
+}
+
+func (a *AuthHandler) Login(ctx context.Context, request schemas.LoginRequestObject) (schemas.LoginResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request, NewLoginValidator, func(ctx context.Context, q db.Querier, user auth.User) (schemas.TokenResponse, error) {
+		pair, err := a.auth.Issue(ctx, user)
+		if err != nil {
+			return schemas.TokenResponse{}, err
+		}
+
+		return NewTokenComposer(pair).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return schemas.Login200JSONResponse(data), nil
+}
+
+func (a *AuthHandler) RefreshToken(ctx context.Context, request schemas.RefreshTokenRequestObject) (schemas.RefreshTokenResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request.Body, NewRefreshTokenValidator, func(ctx context.Context, q db.Querier, refreshToken string) (schemas.TokenResponse, error) {
+		pair, err := a.auth.Refresh(ctx, q, refreshToken)
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return schemas.TokenResponse{}, app.NewMyError(err, app.ErrorCodeInvalidToken)
+		}
+		if err != nil {
+			return schemas.TokenResponse{}, err
+		}
+
+		return NewTokenComposer(pair).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return schemas.RefreshToken200JSONResponse(data), nil
+}
+
+func (a *AuthHandler) Logout(ctx context.Context, request schemas.LogoutRequestObject) (schemas.LogoutResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	_, err := Run(ctx, rctx, request.Body, NewLogoutTokenValidator, func(ctx context.Context, q db.Querier, refreshToken string) (struct{}, error) {
+		claims, _ := auth.ClaimsFromContext(ctx)
+		return struct{}{}, a.auth.Revoke(ctx, claims, refreshToken)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return schemas.Logout204Response{}, nil
+}
+
+var (
+	_ Refiner[schemas.LoginRequestObject, auth.User] = (*LoginValidator)(nil)
+	_ Refiner[*schemas.RefreshTokenRequest, string]  = (*RefreshTokenValidator)(nil)
+	_ Composer[schemas.TokenResponse]                = (*TokenComposer)(nil)
+)
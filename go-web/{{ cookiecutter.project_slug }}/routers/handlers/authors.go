@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"exampleproj/db"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/pagination"
+	"exampleproj/routers/schemas"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// AuthorCreationValidator validates the author creation request, the
+// author is owned by the current user
+type AuthorCreationValidator struct{}
+
+func NewAuthorCreationValidator() Refiner[schemas.CreateAuthorRequestObject, db.Author] {
+	return &AuthorCreationValidator{}
+}
+
+func (a *AuthorCreationValidator) refine(ctx context.Context, request schemas.CreateAuthorRequestObject, q db.Querier) (db.Author, error) {
+	schema := *request.Body
+
+	validate := app.Validator()
+	if err := validate.Struct(schema); err != nil {
+		return db.Author{}, err
+	}
+
+	user, _ := auth.UserFromContext(ctx)
+	return q.CreateAuthor(ctx, db.CreateAuthorParams{
+		Name:   schema.Name,
+		Bio:    bio(schema.Bio),
+		UserID: user.ID,
+	})
+}
+
+// AuthorLookupRefiner loads the author addressed by the schemas.AuthorID of
+// the {id} url param
+type AuthorLookupRefiner struct{}
+
+func NewAuthorLookupRefiner() Refiner[schemas.AuthorID, db.Author] {
+	return &AuthorLookupRefiner{}
+}
+
+// refine loads the author, a missing author is reported as a 404.
+func (a *AuthorLookupRefiner) refine(ctx context.Context, id schemas.AuthorID, q db.Querier) (db.Author, error) {
+	author, err := q.GetAuthor(ctx, id)
+	if err != nil {
+		return db.Author{}, notFound(err, app.ErrorCodeAuthorNotFound)
+	}
+
+	return author, nil
+}
+
+// AuthorOwnerRefiner loads the author and lets its owner through, the other
+// users need the permission
+type AuthorOwnerRefiner struct {
+	AuthorLookupRefiner
+	policy     *auth.Policy
+	permission string
+}
+
+func (a *AuthorOwnerRefiner) refine(ctx context.Context, id schemas.AuthorID, q db.Querier) (db.Author, error) {
+	author, err := a.AuthorLookupRefiner.refine(ctx, id, q)
+	if err != nil {
+		return db.Author{}, err
+	}
+
+	if err := a.policy.AllowOwnerOr(ctx, q, author.UserID, a.permission); err != nil {
+		return db.Author{}, err
+	}
+
+	return author, nil
+}
+
+type AuthorUpdateValidator struct {
+	AuthorOwnerRefiner
+}
+
+// refine validates the update request and applies it to the addressed
+// author, the missing fields are left unchanged.
+func (a *AuthorUpdateValidator) refine(ctx context.Context, request schemas.UpdateAuthorRequestObject, q db.Querier) (db.Author, error) {
+	schema := *request.Body
+
+	validate := app.Validator()
+	if err := validate.Struct(schema); err != nil {
+		return db.Author{}, err
+	}
+
+	author, err := a.AuthorOwnerRefiner.refine(ctx, request.Id, q)
+	if err != nil {
+		return db.Author{}, err
+	}
+
+	if schema.Name != nil {
+		author.Name = *schema.Name
+	}
+	if schema.Bio != nil {
+		author.Bio = bio(schema.Bio)
+	}
+
+	author, err = q.UpdateAuthor(ctx, db.UpdateAuthorParams{Name: author.Name, Bio: author.Bio, ID: author.ID})
+	if err != nil {
+		return db.Author{}, notFound(err, app.ErrorCodeAuthorNotFound)
+	}
+
+	return author, nil
+}
+
+// AuthorListRefiner parses the pagination params of the authors listing
+type AuthorListRefiner struct{}
+
+func NewAuthorListRefiner() Refiner[schemas.ListAuthorsRequestObject, pagination.Params] {
+	return &AuthorListRefiner{}
+}
+
+func (a *AuthorListRefiner) refine(ctx context.Context, request schemas.ListAuthorsRequestObject, q db.Querier) (pagination.Params, error) {
+	params := request.Params
+
+	filters := map[string]string{}
+	if params.Name != nil {
+		filters["name"] = *params.Name
+	}
+
+	return db.AuthorsPagination.Params(params.Limit, params.Cursor, (*string)(params.Sort), filters)
+}
+
+// AuthorComposer renders a db.Author as schemas.Author
+type AuthorComposer struct {
+	schemas.Author
+}
+
+func NewAuthorComposer(author db.Author) Composer[schemas.Author] {
+	return &AuthorComposer{newAuthor(author)}
+}
+
+func (a *AuthorComposer) compose(ctx context.Context, q db.Querier) (schemas.Author, error) {
+	return a.Author, nil
+}
+
+// AuthorListComposer renders a page of authors as schemas.AuthorPage
+type AuthorListComposer struct {
+	Page pagination.Page[db.Author]
+}
+
+func NewAuthorListComposer(page pagination.Page[db.Author]) Composer[listAuthorsResponse] {
+	return &AuthorListComposer{Page: page}
+}
+
+func (a *AuthorListComposer) compose(ctx context.Context, q db.Querier) (listAuthorsResponse, error) {
+	authors := make([]schemas.Author, 0, len(a.Page.Items))
+	for _, author := range a.Page.Items {
+		authors = append(authors, newAuthor(author))
+	}
+
+	return listAuthorsResponse{
+		page: schemas.AuthorPage{Items: authors, NextCursor: a.Page.NextCursor()},
+		link: a.Page.Link("/authors"),
+	}, nil
+}
+
+// listAuthorsResponse sends the Link header only when there is a next page
+type listAuthorsResponse struct {
+	page schemas.AuthorPage
+	link string
+}
+
+func (r listAuthorsResponse) VisitListAuthorsResponse(w http.ResponseWriter) error {
+	return visitPage(w, r.page, r.link)
+}
+
+func newAuthor(author db.Author) schemas.Author {
+	res := schemas.Author{Id: author.ID, Name: author.Name, UserId: author.UserID}
+	if author.Bio.Valid {
+		res.Bio = &author.Bio.String
+	}
+	return res
+}
+
+// bio stores an empty biography as NULL
+func bio(value *string) pgtype.Text {
+	if value == nil || *value == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *value, Valid: true}
+}
+
+func NewAuthorHandler(store db.Store, logger *zap.SugaredLogger, policy *auth.Policy) *AuthorHandler {
+	return &AuthorHandler{
+		store:  store,
+		logger: logger,
+		policy: policy,
+	}
+}
+
+// AuthorHandler implements the author operations of
+// schemas.StrictServerInterface, the API serves them.
+//
+// An author is owned by the user who created it, the owner can update and
+// delete it and so can the users granted the authors permissions.
+type AuthorHandler struct {
+	store  db.Store
+	logger *zap.SugaredLogger
+	policy *auth.Policy
+}
+
+// newOwnerRefiner builds the AuthorOwnerRefiner checking the permission
+func (a *AuthorHandler) newOwnerRefiner(permission string) RefinerFactory[schemas.AuthorID, db.Author] {
+	return func() Refiner[schemas.AuthorID, db.Author] {
+		return &AuthorOwnerRefiner{policy: a.policy, permission: permission}
+	}
+}
+
+func (a *AuthorHandler) newUpdateValidator() Refiner[schemas.UpdateAuthorRequestObject, db.Author] {
+	return &AuthorUpdateValidator{AuthorOwnerRefiner{policy: a.policy, permission: auth.PermissionAuthorsUpdate}}
+}
+
+func (a *AuthorHandler) CreateAuthor(ctx context.Context, request schemas.CreateAuthorRequestObject) (schemas.CreateAuthorResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request, NewAuthorCreationValidator, func(ctx context.Context, q db.Querier, author db.Author) (schemas.Author, error) {
+		return NewAuthorComposer(author).compose(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.CreateAuthor201JSONResponse(data), nil
+}
+
+func (a *AuthorHandler) ListAuthors(ctx context.Context, request schemas.ListAuthorsRequestObject) (schemas.ListAuthorsResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request, NewAuthorListRefiner, func(ctx context.Context, q db.Querier, params pagination.Params) (listAuthorsResponse, error) {
+		page, err := db.ListAuthorsPage(ctx, q, params)
+		if err != nil {
+			return listAuthorsResponse{}, err
+		}
+
+		return NewAuthorListComposer(page).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (a *AuthorHandler) GetAuthor(ctx context.Context, request schemas.GetAuthorRequestObject) (schemas.GetAuthorResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request.Id, NewAuthorLookupRefiner, func(ctx context.Context, q db.Querier, author db.Author) (schemas.Author, error) {
+		return NewAuthorComposer(author).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return schemas.GetAuthor200JSONResponse(data), nil
+}
+
+func (a *AuthorHandler) UpdateAuthor(ctx context.Context, request schemas.UpdateAuthorRequestObject) (schemas.UpdateAuthorResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	data, err := Run(ctx, rctx, request, a.newUpdateValidator, func(ctx context.Context, q db.Querier, author db.Author) (schemas.Author, error) {
+		return NewAuthorComposer(author).compose(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.UpdateAuthor200JSONResponse(data), nil
+}
+
+func (a *AuthorHandler) DeleteAuthor(ctx context.Context, request schemas.DeleteAuthorRequestObject) (schemas.DeleteAuthorResponseObject, error) {
+	rctx := RequestContext{a.store, a.logger}
+	_, err := Run(ctx, rctx, request.Id, a.newOwnerRefiner(auth.PermissionAuthorsDelete), func(ctx context.Context, q db.Querier, author db.Author) (struct{}, error) {
+		return struct{}{}, q.DeleteAuthor(ctx, author.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.DeleteAuthor204Response{}, nil
+}
+
+var (
+	_ Refiner[schemas.CreateAuthorRequestObject, db.Author]        = (*AuthorCreationValidator)(nil)
+	_ Refiner[schemas.AuthorID, db.Author]                         = (*AuthorLookupRefiner)(nil)
+	_ Refiner[schemas.AuthorID, db.Author]                         = (*AuthorOwnerRefiner)(nil)
+	_ Refiner[schemas.UpdateAuthorRequestObject, db.Author]        = (*AuthorUpdateValidator)(nil)
+	_ Refiner[schemas.ListAuthorsRequestObject, pagination.Params] = (*AuthorListRefiner)(nil)
+
+	_ Composer[schemas.Author]      = (*AuthorComposer)(nil)
+	_ Composer[listAuthorsResponse] = (*AuthorListComposer)(nil)
+)
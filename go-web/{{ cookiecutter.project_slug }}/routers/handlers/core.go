@@ -3,66 +3,121 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"exampleproj/db"
 	"exampleproj/internal/app"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 // Refiner refines the input data from the request
 // validate -> refine data -> database
-type Refiner interface {
-	refine(context.Context, *http.Request, *db.Queries) (interface{}, error)
+//
+// In is the request object of the strict server operation, Out is the
+// refined data handed to the BusinessFunc.
+//
+// A refiner serves a single request and may keep its state in its fields,
+// Run builds one per request with the RefinerFactory.
+type Refiner[In, Out any] interface {
+	refine(ctx context.Context, in In, q db.Querier) (Out, error)
 }
 
-type Composer interface {
-	compose(context.Context, *db.Queries) (interface{}, error)
+// RefinerFactory builds the Refiner of a request
+type RefinerFactory[In, Out any] func() Refiner[In, Out]
+
+// Composer composes the response data of type Out, like the refiners a
+// composer serves a single request
+type Composer[Out any] interface {
+	compose(context.Context, db.Querier) (Out, error)
 }
 
+// ComposerFactory builds the Composer of a request from the data it renders
+type ComposerFactory[Data, Out any] func(Data) Composer[Out]
+
+// Handler registers its routes on the router, see routers.AsRoute
 type Handler interface {
-	handle() http.HandlerFunc
 	RegisterRoute(*chi.Mux)
 }
 
 type RequestContext struct {
-	q      *db.Queries
+	store  db.Store
 	logger *zap.SugaredLogger
 }
 
-// Flow define the basic process flow for an API endpoint
-// it should be responsible for
-// - validation
-// - business logic (encapsulated in service)
-func Flow(rctx RequestContext, refiner Refiner, f func(ctx context.Context, refinedData interface{}) (interface{}, error)) func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var refinedData interface{}
-		var err error
-
-		ctx := r.Context()
-		q := rctx.q
-		w.Header().Set("Content-Type", "application/json")
-
-		if refiner != nil {
-			refinedData, err = refiner.refine(ctx, r, q)
-			if err != nil {
-				app.RenderError(w, err)
-				return
-			}
-		}
+// BusinessFunc receives the refined data and the Querier bound to the
+// request transaction, it returns the data to be rendered
+type BusinessFunc[Refined, Out any] func(ctx context.Context, q db.Querier, refinedData Refined) (Out, error)
+
+type runOptions struct {
+	tx bool
+}
+
+// RunOption customizes the behaviour of Run
+type RunOption func(*runOptions)
+
+// WithoutTx opts out of the request transaction, meant for read-only
+// endpoints which gain nothing from it
+func WithoutTx() RunOption {
+	return func(o *runOptions) {
+		o.tx = false
+	}
+}
+
+// Run is the process flow of the strict server operations, it refines the
+// request object and runs the business function, then returns the composed
+// data. The operation wraps it into its response object which carries the
+// status code.
+//
+// The refiner and the business function share a transaction which is
+// committed when both succeed and rolled back otherwise, so a failure in
+// the composer never leaves a half-done write behind.
+func Run[In, Refined, Out any](ctx context.Context, rctx RequestContext, in In, newRefiner RefinerFactory[In, Refined], f BusinessFunc[Refined, Out], opts ...RunOption) (Out, error) {
+	options := runOptions{tx: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
-		// return the composer output
-		data, err := f(ctx, refinedData)
+	var data Out
+
+	exec := func(q db.Querier) error {
+		refinedData, err := newRefiner().refine(ctx, in, q)
 		if err != nil {
-			app.RenderError(w, err)
-			return
+			return err
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err = json.NewEncoder(w).Encode(data); err != nil {
-			app.RenderError(w, err)
-		}
+		// keep the composer output
+		data, err = f(ctx, q, refinedData)
+		return err
+	}
+
+	var err error
+	if options.tx {
+		err = rctx.store.ExecTx(ctx, exec)
+	} else {
+		err = exec(rctx.store)
+	}
+	return data, err
+}
+
+// visitPage writes a page of a strict server listing, with the Link header
+// of the next page if any
+func visitPage(w http.ResponseWriter, page interface{}, link string) error {
+	w.Header().Set("Content-Type", "application/json")
+	if link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(page)
+}
+
+// notFound maps pgx.ErrNoRows onto a 404 carrying the given error code
+func notFound(err error, code int) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return app.NewMyError(err, code)
 	}
+	return err
 }
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"exampleproj/config"
+
+	"github.com/flowchartsman/swaggerui"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/asyncapi.html
+var templates embed.FS
+
+// the template delimiters don't clash with the cookiecutter ones
+var asyncAPITemplate = template.Must(
+	template.New("asyncapi.html").Delims("[[", "]]").ParseFS(templates, "templates/asyncapi.html"),
+)
+
+// Docs are the api documents embedded by the binary
+type Docs struct {
+	OpenAPI  []byte
+	AsyncAPI []byte
+}
+
+// DocsHandler serves the OpenAPI and AsyncAPI documents and their html
+// views, everything is self-hosted so the docs work without internet.
+//
+// Routes:
+// - /openapi.yaml and /asyncapi.yaml: the raw documents
+// - /docs/openapi/: the Swagger UI
+// - /docs/asyncapi: the AsyncAPI view
+//
+// The docs are disabled in production.
+type DocsHandler struct {
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+	docs   Docs
+	// asyncAPIPage is rendered once, the document can't change
+	asyncAPIPage []byte
+}
+
+func NewDocsHandler(cfg *config.Config, logger *zap.SugaredLogger, docs Docs) (*DocsHandler, error) {
+	var page bytes.Buffer
+	view, err := newAsyncAPIView(docs.AsyncAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err := asyncAPITemplate.Execute(&page, view); err != nil {
+		return nil, err
+	}
+
+	return &DocsHandler{
+		cfg:          cfg,
+		logger:       logger,
+		docs:         docs,
+		asyncAPIPage: page.Bytes(),
+	}, nil
+}
+
+func (d *DocsHandler) RegisterRoute(r *chi.Mux) {
+	if d.cfg.App.Env == config.Prod {
+		return
+	}
+
+	r.Get("/openapi.yaml", serveDocument(d.docs.OpenAPI))
+	r.Get("/asyncapi.yaml", serveDocument(d.docs.AsyncAPI))
+	r.Get("/docs", http.RedirectHandler("/docs/openapi/", http.StatusMovedPermanently).ServeHTTP)
+	r.Mount("/docs/openapi", http.StripPrefix("/docs/openapi", swaggerui.Handler(d.docs.OpenAPI)))
+	r.Get("/docs/asyncapi", d.handle())
+}
+
+func (d *DocsHandler) handle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(d.asyncAPIPage)
+	}
+}
+
+func serveDocument(doc []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(doc)
+	}
+}
+
+type asyncAPIRef struct {
+	Ref string `yaml:"$ref"`
+}
+
+// name is the last segment of the reference, ex. #/channels/ping is ping
+func (r asyncAPIRef) name() string {
+	return r.Ref[strings.LastIndex(r.Ref, "/")+1:]
+}
+
+// asyncAPIDocument is the part of an AsyncAPI 3 document the view renders
+type asyncAPIDocument struct {
+	AsyncAPI string `yaml:"asyncapi"`
+	Info     struct {
+		Title       string `yaml:"title"`
+		Version     string `yaml:"version"`
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Servers map[string]struct {
+		Host        string `yaml:"host"`
+		Protocol    string `yaml:"protocol"`
+		Description string `yaml:"description"`
+	} `yaml:"servers"`
+	Channels map[string]struct {
+		Address  string                 `yaml:"address"`
+		Messages map[string]asyncAPIRef `yaml:"messages"`
+	} `yaml:"channels"`
+	Operations map[string]struct {
+		Action  string      `yaml:"action"`
+		Channel asyncAPIRef `yaml:"channel"`
+		Reply   *struct {
+			Channel asyncAPIRef `yaml:"channel"`
+		} `yaml:"reply"`
+	} `yaml:"operations"`
+	Components struct {
+		Messages map[string]struct {
+			Payload interface{} `yaml:"payload"`
+		} `yaml:"messages"`
+	} `yaml:"components"`
+}
+
+type asyncAPIView struct {
+	Version    string
+	Info       struct{ Title, Version, Description string }
+	Servers    []asyncAPIServerView
+	Operations []asyncAPIOperationView
+	Channels   []asyncAPIChannelView
+	Messages   []asyncAPIMessageView
+}
+
+type asyncAPIServerView struct {
+	Name, Host, Protocol, Description string
+}
+
+type asyncAPIOperationView struct {
+	Name, Action, Channel, Reply string
+}
+
+type asyncAPIChannelView struct {
+	Name, Address string
+	Messages      []string
+}
+
+type asyncAPIMessageView struct {
+	Name, Payload string
+}
+
+// newAsyncAPIView flattens the document into sorted lists, the channels are
+// shown by their address
+func newAsyncAPIView(doc []byte) (*asyncAPIView, error) {
+	var spec asyncAPIDocument
+	if err := yaml.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+
+	view := &asyncAPIView{Version: spec.AsyncAPI}
+	view.Info.Title = spec.Info.Title
+	view.Info.Version = spec.Info.Version
+	view.Info.Description = spec.Info.Description
+
+	address := func(ref asyncAPIRef) string {
+		if channel, ok := spec.Channels[ref.name()]; ok && channel.Address != "" {
+			return channel.Address
+		}
+		return ref.name()
+	}
+
+	for _, name := range sortedKeys(spec.Servers) {
+		server := spec.Servers[name]
+		view.Servers = append(view.Servers, asyncAPIServerView{name, server.Host, server.Protocol, server.Description})
+	}
+
+	for _, name := range sortedKeys(spec.Operations) {
+		operation := spec.Operations[name]
+		var reply string
+		if operation.Reply != nil {
+			reply = address(operation.Reply.Channel)
+		}
+		view.Operations = append(view.Operations, asyncAPIOperationView{name, operation.Action, address(operation.Channel), reply})
+	}
+
+	for _, name := range sortedKeys(spec.Channels) {
+		channel := spec.Channels[name]
+		var messages []string
+		for _, key := range sortedKeys(channel.Messages) {
+			messages = append(messages, channel.Messages[key].name())
+		}
+		view.Channels = append(view.Channels, asyncAPIChannelView{name, channel.Address, messages})
+	}
+
+	for _, name := range sortedKeys(spec.Components.Messages) {
+		payload, err := json.MarshalIndent(spec.Components.Messages[name].Payload, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		view.Messages = append(view.Messages, asyncAPIMessageView{name, string(payload)})
+	}
+
+	return view, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ Handler = (*DocsHandler)(nil)
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"exampleproj/internal/health"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HealthHandler serves the probes of the orchestrator
+//
+// Routes:
+// - /livez: the process is up, a failure means it must be restarted
+// - /readyz: the dependencies are reachable, a failure means the traffic
+// must go to the other instances
+//
+// Both answer the health.Report as json, with a 503 when it fails.
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+func (h *HealthHandler) RegisterRoute(r *chi.Mux) {
+	r.Get("/livez", h.livez())
+	r.Get("/readyz", h.handle())
+}
+
+func (h *HealthHandler) handle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.registry.Readiness(r.Context()))
+	}
+}
+
+func (h *HealthHandler) livez() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.registry.Liveness())
+	}
+}
+
+func writeReport(w http.ResponseWriter, report health.Report) {
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+var _ Handler = (*HealthHandler)(nil)
@@ -2,15 +2,14 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"exampleproj/db"
+	"exampleproj/internal/app"
+	"exampleproj/internal/pagination"
 	"exampleproj/routers/schemas"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
-	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -19,27 +18,52 @@ type UserCreationValidator struct {
 	Schema schemas.CreateUserRequest `json:"-"`
 }
 
+func NewUserCreationValidator() Refiner[schemas.CreateUserRequestObject, db.User] {
+	return &UserCreationValidator{}
+}
+
 // refine validates and refines the user creation request.
+// The passwords must match, the stored password is hashed and an email
+// which is already registered is rejected with a 409.
 //
 // It takes the following parameters:
 // - ctx: the context.Context object for the request.
-// - r: the http.Request object containing the user creation request.
-// - q: the db.Queries object for accessing the database.
+// - in: the schemas.CreateUserRequestObject of the request.
+// - q: the db.Querier for accessing the database.
 //
-// It returns an interface{} representing the created user and an error if any.
-func (u *UserCreationValidator) refine(ctx context.Context, r *http.Request, q *db.Queries) (interface{}, error) {
+// It returns the created db.User and an error if any.
+func (u *UserCreationValidator) refine(ctx context.Context, in schemas.CreateUserRequestObject, q db.Querier) (db.User, error) {
 
-	if err := json.NewDecoder(r.Body).Decode(&u.Schema); err != nil {
-		return nil, err
-	}
+	u.Schema = *in.Body
 
-	validate := validator.New()
+	validate := app.Validator()
 	if err := validate.Struct(u.Schema); err != nil {
-		return nil, err
+		return db.User{}, err
+	}
+
+	if u.Schema.Password != u.Schema.RepeatedPassword {
+		return db.User{}, app.NewMyError(app.Public(errors.New("passwords do not match")), app.ErrorCodeInvalidPassword)
+	}
+
+	hash, err := app.HashPassword(u.Schema.Password)
+	if err != nil {
+		return db.User{}, err
 	}
 
 	u.Model.Name = u.Schema.Name
-	return q.CreateUser(ctx, u.Model.Name)
+	u.Model.Email = u.Schema.Email
+	u.Model.PasswordHash = hash
+
+	user, err := q.CreateUser(ctx, db.CreateUserParams{
+		Name:         u.Model.Name,
+		Email:        u.Model.Email,
+		PasswordHash: u.Model.PasswordHash,
+	})
+	if db.IsUniqueViolation(err) {
+		return db.User{}, app.NewMyError(err, app.ErrorCodeEmailExists)
+	}
+
+	return user, err
 }
 
 type UserCreationComposer struct {
@@ -47,62 +71,238 @@ type UserCreationComposer struct {
 	Seed int    `json:"seed"`
 }
 
+func NewUserCreationComposer(user db.User) Composer[schemas.CreateUserResponse] {
+	// We can do anything between refinedData and composer
+	return &UserCreationComposer{Name: user.Name, Seed: 1233}
+}
+
 // compose composes the user creation response.
-// Currently, it just returns the UserCreationComposer object.
+// Currently, it just returns the fields of the UserCreationComposer object.
 // However, you can add more logic here if needed.
 //
 // Parameters:
 // - ctx: the context.Context object for the request.
-// - q: the db.Queries object for accessing the database.
+// - q: the db.Querier for accessing the database.
 //
 // Returns:
-// - interface{}: the composed UserCreationComposer object.
+// - schemas.CreateUserResponse: the composed response.
 // - error: a nil error.
-func (u *UserCreationComposer) compose(ctx context.Context, q *db.Queries) (interface{}, error) {
-	return u, nil
+func (u *UserCreationComposer) compose(ctx context.Context, q db.Querier) (schemas.CreateUserResponse, error) {
+	return schemas.CreateUserResponse{Name: u.Name, Seed: u.Seed}, nil
+}
+
+// UserLookupRefiner loads the user addressed by the schemas.UserID of the
+// {id} url param
+type UserLookupRefiner struct{}
+
+func NewUserLookupRefiner() Refiner[schemas.UserID, db.User] {
+	return &UserLookupRefiner{}
+}
+
+// refine loads the user, a missing user is reported as a 404.
+func (u *UserLookupRefiner) refine(ctx context.Context, id schemas.UserID, q db.Querier) (db.User, error) {
+	user, err := q.GetUser(ctx, id)
+	if err != nil {
+		return db.User{}, notFound(err, app.ErrorCodeUserNotFound)
+	}
+
+	return user, nil
+}
+
+type UserUpdateValidator struct {
+	UserLookupRefiner
 }
 
-func NewUserHandler(conn *pgx.Conn, logger *zap.SugaredLogger) *UserHandler {
+func NewUserUpdateValidator() Refiner[schemas.UpdateUserRequestObject, db.User] {
+	return &UserUpdateValidator{}
+}
+
+// refine validates the update request and applies it to the addressed user.
+func (u *UserUpdateValidator) refine(ctx context.Context, request schemas.UpdateUserRequestObject, q db.Querier) (db.User, error) {
+	schema := *request.Body
+
+	validate := app.Validator()
+	if err := validate.Struct(schema); err != nil {
+		return db.User{}, err
+	}
+
+	user, err := u.UserLookupRefiner.refine(ctx, request.Id, q)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	if schema.Name != nil {
+		user.Name = *schema.Name
+	}
+
+	user, err = q.UpdateUser(ctx, db.UpdateUserParams{Name: user.Name, ID: user.ID})
+	if err != nil {
+		return db.User{}, notFound(err, app.ErrorCodeUserNotFound)
+	}
+
+	return user, nil
+}
+
+// UserComposer renders a db.User as the public schemas.User
+type UserComposer struct {
+	schemas.User
+}
+
+func NewUserComposer(user db.User) Composer[schemas.User] {
+	return &UserComposer{schemas.User{Id: user.ID, Name: user.Name}}
+}
+
+func (u *UserComposer) compose(ctx context.Context, q db.Querier) (schemas.User, error) {
+	return u.User, nil
+}
+
+// UserListRefiner parses the pagination params of the users listing
+type UserListRefiner struct{}
+
+func NewUserListRefiner() Refiner[schemas.ListUsersRequestObject, pagination.Params] {
+	return &UserListRefiner{}
+}
+
+func (u *UserListRefiner) refine(ctx context.Context, request schemas.ListUsersRequestObject, q db.Querier) (pagination.Params, error) {
+	params := request.Params
+
+	filters := map[string]string{}
+	if params.Name != nil {
+		filters["name"] = *params.Name
+	}
+
+	return db.UsersPagination.Params(params.Limit, params.Cursor, (*string)(params.Sort), filters)
+}
+
+// UserListComposer renders a page of users as schemas.UserPage
+type UserListComposer struct {
+	Page pagination.Page[db.User]
+}
+
+func NewUserListComposer(page pagination.Page[db.User]) Composer[listUsersResponse] {
+	return &UserListComposer{Page: page}
+}
+
+func (u *UserListComposer) compose(ctx context.Context, q db.Querier) (listUsersResponse, error) {
+	users := make([]schemas.User, 0, len(u.Page.Items))
+	for _, user := range u.Page.Items {
+		users = append(users, schemas.User{Id: user.ID, Name: user.Name})
+	}
+
+	return listUsersResponse{
+		page: schemas.UserPage{Items: users, NextCursor: u.Page.NextCursor()},
+		link: u.Page.Link("/users"),
+	}, nil
+}
+
+// listUsersResponse sends the Link header only when there is a next page,
+// the generated response always sets it
+type listUsersResponse struct {
+	page schemas.UserPage
+	link string
+}
+
+func (r listUsersResponse) VisitListUsersResponse(w http.ResponseWriter) error {
+	return visitPage(w, r.page, r.link)
+}
+
+func NewUserHandler(store db.Store, logger *zap.SugaredLogger) *UserHandler {
 
 	return &UserHandler{
-		q:        db.New(conn),
-		logger:   logger,
-		refiner:  &UserCreationValidator{},
-		composer: &UserCreationComposer{},
+		store:       store,
+		logger:      logger,
+		newRefiner:  NewUserCreationValidator,
+		newComposer: NewUserCreationComposer,
 	}
 }
 
-// UserHandler is a struct that implements the HandlerFunc interface.
+// UserHandler is a struct that implements the user operations of
+// schemas.StrictServerInterface, the API serves them.
 //
 // It contains the necessary dependencies for handling user creation requests.
 // The refiner is responsible for validating the user creation request,
-// while the composer is responsible for composing the response.
+// while the composer is responsible for composing the response. Both are
+// built for each request, the handler itself is shared by all of them.
 //
 // Fields:
-// - q: the database queries interface
+// - store: the database store, each request runs in its own transaction
 // - logger: the logger used for logging
-// - refiner: the validator for user creation request
-// - composer: the composer for user creation response
+// - newRefiner: builds the validator for user creation request
+// - newComposer: builds the composer for user creation response
 type UserHandler struct {
-	q        *db.Queries
-	logger   *zap.SugaredLogger
-	refiner  *UserCreationValidator
-	composer *UserCreationComposer
+	store       db.Store
+	logger      *zap.SugaredLogger
+	newRefiner  RefinerFactory[schemas.CreateUserRequestObject, db.User]
+	newComposer ComposerFactory[db.User, schemas.CreateUserResponse]
 }
 
-func (u *UserHandler) RegisterRoute(r *chi.Mux) {
-	r.Post("/users", u.handle())
+func (u *UserHandler) CreateUser(ctx context.Context, request schemas.CreateUserRequestObject) (schemas.CreateUserResponseObject, error) {
+	rctx := RequestContext{u.store, u.logger}
+	data, err := Run(ctx, rctx, request, u.newRefiner, func(ctx context.Context, q db.Querier, user db.User) (schemas.CreateUserResponse, error) {
+		return u.newComposer(user).compose(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.CreateUser201JSONResponse(data), nil
+}
+
+func (u *UserHandler) ListUsers(ctx context.Context, request schemas.ListUsersRequestObject) (schemas.ListUsersResponseObject, error) {
+	rctx := RequestContext{u.store, u.logger}
+	data, err := Run(ctx, rctx, request, NewUserListRefiner, func(ctx context.Context, q db.Querier, params pagination.Params) (listUsersResponse, error) {
+		page, err := db.ListUsersPage(ctx, q, params)
+		if err != nil {
+			return listUsersResponse{}, err
+		}
+
+		return NewUserListComposer(page).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
-func (u *UserHandler) handle() http.HandlerFunc {
-	rctx := RequestContext{u.q, u.logger}
-	return Flow(rctx, u.refiner, func(ctx context.Context, refinedData interface{}) (interface{}, error) {
-		user := refinedData.(db.User)
+func (u *UserHandler) GetUser(ctx context.Context, request schemas.GetUserRequestObject) (schemas.GetUserResponseObject, error) {
+	rctx := RequestContext{u.store, u.logger}
+	data, err := Run(ctx, rctx, request.Id, NewUserLookupRefiner, func(ctx context.Context, q db.Querier, user db.User) (schemas.User, error) {
+		return NewUserComposer(user).compose(ctx, q)
+	}, WithoutTx())
+	if err != nil {
+		return nil, err
+	}
+	return schemas.GetUser200JSONResponse(data), nil
+}
 
-		// We can do anything between refinedData and composer
-		u.composer.Name = user.Name
-		u.composer.Seed = 1233
+func (u *UserHandler) UpdateUser(ctx context.Context, request schemas.UpdateUserRequestObject) (schemas.UpdateUserResponseObject, error) {
+	rctx := RequestContext{u.store, u.logger}
+	data, err := Run(ctx, rctx, request, NewUserUpdateValidator, func(ctx context.Context, q db.Querier, user db.User) (schemas.User, error) {
+		return NewUserComposer(user).compose(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.UpdateUser200JSONResponse(data), nil
+}
 
-		return u.composer.compose(ctx, u.q)
+func (u *UserHandler) DeleteUser(ctx context.Context, request schemas.DeleteUserRequestObject) (schemas.DeleteUserResponseObject, error) {
+	rctx := RequestContext{u.store, u.logger}
+	_, err := Run(ctx, rctx, request.Id, NewUserLookupRefiner, func(ctx context.Context, q db.Querier, user db.User) (struct{}, error) {
+		return struct{}{}, q.DeleteUser(ctx, user.ID)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas.DeleteUser204Response{}, nil
 }
+
+var (
+	_ Refiner[schemas.CreateUserRequestObject, db.User]          = (*UserCreationValidator)(nil)
+	_ Refiner[schemas.UserID, db.User]                           = (*UserLookupRefiner)(nil)
+	_ Refiner[schemas.UpdateUserRequestObject, db.User]          = (*UserUpdateValidator)(nil)
+	_ Refiner[schemas.ListUsersRequestObject, pagination.Params] = (*UserListRefiner)(nil)
+
+	_ Composer[schemas.CreateUserResponse] = (*UserCreationComposer)(nil)
+	_ Composer[schemas.User]               = (*UserComposer)(nil)
+	_ Composer[listUsersResponse]          = (*UserListComposer)(nil)
+)
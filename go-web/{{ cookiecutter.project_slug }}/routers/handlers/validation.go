@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"exampleproj/internal/app"
+	"exampleproj/routers/schemas"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+func init() {
+	// kin-openapi only checks the formats it is told about
+	openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+}
+
+// schemaFieldTags names the failed schema keywords the way the validator
+// tags of the field errors do, so clients see the same rules either way
+var schemaFieldTags = map[string]string{
+	"required":  "required",
+	"minLength": "min",
+	"maxLength": "max",
+	"minimum":   "gte",
+	"maximum":   "lte",
+	"minItems":  "min",
+	"maxItems":  "max",
+	"enum":      "oneof",
+}
+
+// RequestValidator validates the path params, query params, headers and
+// json bodies of the requests against the operations of the embedded
+// swagger.yml, so the refiners only see requests matching the spec.
+type RequestValidator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+}
+
+// NewRequestValidator loads the embedded spec, a broken spec fails the startup
+func NewRequestValidator() (*RequestValidator, error) {
+	spec, err := schemas.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// match the requests whatever the host serving them
+	spec.Servers = nil
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestValidator{
+		router: router,
+		options: &openapi3filter.Options{
+			MultiError: true,
+			// the bearerAuth security is enforced by the auth middlewares
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// Middleware rejects the requests which don't match their operation with a
+// 400 listing the invalid fields, the requests outside the spec pass through
+func (v *RequestValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    v.options,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			app.RenderError(w, r, requestValidationError(err))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestValidationError maps the errors of openapi3filter onto the error
+// catalog, the schema violations become field errors
+func requestValidationError(err error) error {
+	var errs openapi3.MultiError
+	if !errors.As(err, &errs) {
+		errs = openapi3.MultiError{err}
+	}
+
+	var fields []schemas.FieldError
+	for _, e := range errs {
+		var reqErr *openapi3filter.RequestError
+		if !errors.As(e, &reqErr) {
+			return e
+		}
+
+		var schemaErrs []*openapi3.SchemaError
+		collectSchemaErrors(reqErr.Err, &schemaErrs)
+
+		switch {
+		case len(schemaErrs) > 0:
+			for _, schemaErr := range schemaErrs {
+				fields = append(fields, schemaFieldError(reqErr, schemaErr))
+			}
+		case reqErr.Parameter != nil:
+			return app.NewMyErrorf(app.ErrorCodeInvalidParam, reqErr.Parameter.Name)
+		default:
+			// a missing or malformed body
+			return app.NewMyError(app.Public(reqErr), app.ErrorCodeInvalidBody)
+		}
+	}
+
+	return app.NewMyErrorWithFields(err, app.ErrorCodeValidation, fields)
+}
+
+func collectSchemaErrors(err error, found *[]*openapi3.SchemaError) {
+	var errs openapi3.MultiError
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			collectSchemaErrors(e, found)
+		}
+		return
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		*found = append(*found, schemaErr)
+	}
+}
+
+func schemaFieldError(reqErr *openapi3filter.RequestError, schemaErr *openapi3.SchemaError) schemas.FieldError {
+	field := strings.Join(schemaErr.JSONPointer(), ".")
+	if reqErr.Parameter != nil {
+		field = reqErr.Parameter.Name
+	}
+
+	tag, ok := schemaFieldTags[schemaErr.SchemaField]
+	switch {
+	case schemaErr.SchemaField == "format" && schemaErr.Schema != nil:
+		tag = schemaErr.Schema.Format
+	case !ok:
+		tag = schemaErr.SchemaField
+	}
+
+	return schemas.FieldError{
+		Field:   field,
+		Tag:     tag,
+		Message: field + ": " + schemaErr.Reason,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"exampleproj/cache"
 	"exampleproj/events"
 	"exampleproj/internal/app"
+	"exampleproj/internal/shutdown"
 	"context"
 	"log"
 	"net/http"
@@ -87,20 +88,18 @@ type WebsocketHandler struct {
 	rdb *redis.Client
 }
 
-func NewWebsocketHandler(lc fx.Lifecycle, rdb *redis.Client) *WebsocketHandler {
-
-	hub := app.NewHub()
+func NewWebsocketHandler(lc fx.Lifecycle, rdb *redis.Client, coordinator *shutdown.Coordinator, hub *app.Hub) *WebsocketHandler {
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go hub.Run()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
-			return nil
-		},
 	})
 
+	// the clients are sent a close frame once the server stops accepting
+	coordinator.Register(shutdown.PhaseConnections, "websocket hub", hub.Shutdown)
+
 	return &WebsocketHandler{
 		hub: hub,
 		rdb: rdb,
@@ -0,0 +1,303 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"exampleproj/config"
+	"exampleproj/internal/app"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+	"go.uber.org/zap"
+	"golang.org/x/net/http/httpguts"
+)
+
+// compressibleTypes are the content types Compress encodes
+var compressibleTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/javascript",
+	"application/javascript",
+	"application/json",
+	"application/problem+json",
+	"application/yaml",
+	"image/svg+xml",
+}
+
+// Middlewares is the chain of middlewares enabled by cfg.MIDDLEWARE, the
+// access log wraps the recovery so the panics are logged as 500s.
+func Middlewares(cfg *config.Config, logger *zap.SugaredLogger) (chi.Middlewares, error) {
+	conf := cfg.MIDDLEWARE
+	proxies, err := parseTrustedProxies(conf.TRUSTED_PROXIES)
+	if err != nil {
+		return nil, err
+	}
+	format, err := app.ParseErrorFormat(cfg.App.ERROR_FORMAT)
+	if err != nil {
+		return nil, err
+	}
+
+	mws := chi.Middlewares{
+		middleware.RequestID,
+		RealIP(proxies),
+		app.ErrorFormatMiddleware(format),
+	}
+
+	if conf.ACCESS_LOG {
+		mws = append(mws, AccessLog(logger))
+	}
+	if conf.RECOVER {
+		mws = append(mws, Recoverer(logger))
+	}
+
+	if conf.CORS.ENABLED {
+		mws = append(mws, cors.Handler(cors.Options{
+			AllowedOrigins:   conf.CORS.ALLOWED_ORIGINS,
+			AllowedMethods:   conf.CORS.ALLOWED_METHODS,
+			AllowedHeaders:   conf.CORS.ALLOWED_HEADERS,
+			ExposedHeaders:   conf.CORS.EXPOSED_HEADERS,
+			AllowCredentials: conf.CORS.ALLOW_CREDENTIALS,
+			MaxAge:           conf.CORS.MAX_AGE,
+		}))
+	}
+
+	if conf.COMPRESS.ENABLED {
+		compressor := middleware.NewCompressor(conf.COMPRESS.LEVEL, compressibleTypes...)
+		// the encoder set last is preferred when the client accepts both
+		compressor.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+			return brotli.NewWriterLevel(w, level)
+		})
+		mws = append(mws, compressor.Handler)
+	}
+
+	routes, err := parseRouteTimeouts(conf.ROUTE_TIMEOUTS)
+	if err != nil {
+		return nil, err
+	}
+	if conf.TIMEOUT > 0 || len(routes) > 0 {
+		mws = append(mws, Timeout(conf.TIMEOUT, routes))
+	}
+
+	return mws, nil
+}
+
+// AccessLog logs a line per request with its request id
+func AccessLog(logger *zap.SugaredLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ctx, renderedError := app.WithRenderedError(r.Context())
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					// nothing written, net/http answers 200
+					status = http.StatusOK
+				}
+
+				log := logger.Infow
+				if status >= http.StatusInternalServerError {
+					log = logger.Errorw
+				}
+				fields := []any{
+					"request_id", middleware.GetReqID(r.Context()),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"status", status,
+					"bytes", ww.BytesWritten(),
+					"duration", time.Since(start),
+					"remote", r.RemoteAddr,
+				}
+				if err := renderedError(); err != nil {
+					// the cause hidden from the client
+					fields = append(fields, "error", err.Error())
+				}
+				log("request", fields...)
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
+
+// Recoverer turns the panics of the handlers into a 500 rendered by
+// app.RenderError instead of dropping the connection
+func Recoverer(logger *zap.SugaredLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// net/http aborts the response silently
+					panic(rec)
+				}
+
+				logger.Errorw("panic serving request",
+					"request_id", middleware.GetReqID(r.Context()),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"panic", rec,
+					"stack", string(debug.Stack()),
+				)
+
+				// a hijacked connection can't be answered, nor a response
+				// whose header is already sent
+				if httpguts.HeaderValuesContainsToken(r.Header["Connection"], "Upgrade") || ww.Status() != 0 {
+					return
+				}
+				app.RenderError(w, r, fmt.Errorf("panic: %v", rec))
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// parseTrustedProxies parses the ips and cidrs of the trusted proxies
+func parseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, want an ip or a cidr", value)
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return proxies, nil
+}
+
+// RealIP sets the RemoteAddr of the requests sent by a trusted proxy to the
+// client ip of their X-Forwarded-For, the last hop not being a trusted proxy,
+// or of their X-Real-IP. The headers of the other peers are ignored, they
+// could pick any ip to get around the rate limits.
+func RealIP(proxies []netip.Prefix) func(http.Handler) http.Handler {
+	trusted := func(ip string) bool {
+		addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+		if err != nil {
+			return false
+		}
+		for _, proxy := range proxies {
+			if proxy.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil || !trusted(peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var ip string
+			hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := strings.TrimSpace(hops[i])
+				if hop == "" {
+					continue
+				}
+				ip = hop
+				if !trusted(hop) {
+					break
+				}
+			}
+			if ip == "" {
+				ip = strings.TrimSpace(r.Header.Get("X-Real-IP"))
+			}
+
+			if addr, err := netip.ParseAddr(ip); err == nil {
+				r.RemoteAddr = addr.Unmap().String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type routeTimeout struct {
+	prefix  string
+	timeout time.Duration
+}
+
+// parseRouteTimeouts parses the prefix=duration overrides, the longest
+// prefixes come first
+func parseRouteTimeouts(values []string) ([]routeTimeout, error) {
+	routes := make([]routeTimeout, 0, len(values))
+	for _, value := range values {
+		prefix, duration, ok := strings.Cut(value, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid route timeout %q, want /prefix=duration", value)
+		}
+
+		timeout, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route timeout %q: %w", value, err)
+		}
+		routes = append(routes, routeTimeout{strings.TrimSuffix(prefix, "/"), timeout})
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+	return routes, nil
+}
+
+// Timeout cancels the context of the requests after timeout, or the timeout
+// of the longest route prefix matching the path. A 0 timeout disables it.
+//
+// The handler still runs to its end, it has to give up on the cancelled
+// context. When it didn't answer, a 503 is rendered.
+func Timeout(timeout time.Duration, routes []routeTimeout) func(http.Handler) http.Handler {
+	timeoutOf := func(path string) time.Duration {
+		for _, route := range routes {
+			if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+				return route.timeout
+			}
+		}
+		return timeout
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := timeoutOf(r.URL.Path)
+			if d <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if ww.Status() == 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				app.RenderError(w, r, ctx.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"exampleproj/config"
+	"exampleproj/db"
+	"exampleproj/internal/admin"
+	"exampleproj/internal/shutdown"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type AdminTestSuite struct {
+	suite.Suite
+	cfg   *config.Config
+	level zap.AtomicLevel
+	h     http.Handler
+}
+
+func TestAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
+
+func (a *AdminTestSuite) SetupTest() {
+	a.cfg = config.NewConfig(config.NewViper(nil))
+	a.cfg.AUTH.SECRET = "top-secret"
+	a.cfg.DB.PASSWORD = "hunter2"
+	a.cfg.WEB3.BLASTSCAN_API_KEY = ""
+	a.level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	a.h = admin.NewHandler(a.cfg, a.level, nil, nil)
+}
+
+func (a *AdminTestSuite) do(method, path, body string) (*http.Response, string) {
+	w := httptest.NewRecorder()
+	a.h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	b, err := io.ReadAll(w.Body)
+	a.Require().NoError(err)
+	return w.Result(), string(b)
+}
+
+func (a *AdminTestSuite) TestProfiler() {
+	resp, body := a.do("GET", "/debug/pprof/", "")
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.Contains(body, "goroutine")
+
+	resp, body = a.do("GET", "/debug/vars", "")
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.Contains(body, "memstats")
+}
+
+func (a *AdminTestSuite) TestMetrics() {
+	resp, body := a.do("GET", "/metrics", "")
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.Contains(body, "go_goroutines")
+}
+
+func (a *AdminTestSuite) TestConfigRedactsTheSecrets() {
+	resp, body := a.do("GET", "/config", "")
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.NotContains(body, "top-secret")
+	a.NotContains(body, "hunter2")
+
+	var view map[string]map[string]any
+	a.Require().NoError(json.Unmarshal([]byte(body), &view))
+	a.Equal(config.RedactedValue, view["auth"]["secret"])
+	a.Equal(config.RedactedValue, view["db"]["password"])
+	// the unset secrets show they are unset
+	a.Equal("", view["web3"]["blastscan_api_key"])
+	a.Equal("8080", view["app"]["port"])
+	a.Equal("15m0s", view["auth"]["access_token_ttl"])
+}
+
+func (a *AdminTestSuite) TestPoolStats() {
+	resp, _ := a.do("GET", "/db/stats", "")
+	a.Equal(http.StatusNotFound, resp.StatusCode)
+
+	lc := fxtest.NewLifecycle(a.T())
+	store, err := NewTestStore(lc, a.cfg)
+	a.Require().NoError(err)
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	w := httptest.NewRecorder()
+	admin.NewHandler(a.cfg, a.level, nil, store).ServeHTTP(w, httptest.NewRequest("GET", "/db/stats", nil))
+	a.Equal(http.StatusOK, w.Code)
+
+	var stats db.PoolStats
+	a.Require().NoError(json.Unmarshal(w.Body.Bytes(), &stats))
+	a.Greater(stats.MaxConns, int32(0))
+}
+
+func (a *AdminTestSuite) TestLogLevel() {
+	_, body := a.do("GET", "/log/level", "")
+	a.JSONEq(`{"level": "info"}`, body)
+	a.False(a.level.Enabled(zapcore.DebugLevel))
+
+	resp, _ := a.do("PUT", "/log/level", `{"level": "debug"}`)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.True(a.level.Enabled(zapcore.DebugLevel))
+
+	resp, _ = a.do("PUT", "/log/level", `{"level": "loud"}`)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (a *AdminTestSuite) TestServer() {
+	a.cfg.ADMIN.ADDR = "127.0.0.1"
+	a.cfg.ADMIN.PORT = freePort(a.T())
+
+	lc := fxtest.NewLifecycle(a.T())
+	logger := zap.NewNop().Sugar()
+	coordinator := shutdown.NewCoordinator(a.cfg, logger)
+	server := admin.NewServer(lc, logger, a.cfg, a.level, coordinator, nil, nil)
+	a.Require().NotNil(server)
+	lc.RequireStart()
+
+	resp, err := http.Get("http://" + server.Addr + "/config")
+	a.Require().NoError(err)
+	resp.Body.Close()
+	a.Equal(http.StatusOK, resp.StatusCode)
+
+	// the admin server is stopped with the app
+	a.NoError(coordinator.Shutdown(context.Background()))
+	_, err = http.Get("http://" + server.Addr + "/config")
+	a.Error(err)
+	lc.RequireStop()
+}
+
+func (a *AdminTestSuite) TestDisabled() {
+	a.cfg.ADMIN.ENABLED = false
+	logger := zap.NewNop().Sugar()
+	a.Nil(admin.NewServer(fxtest.NewLifecycle(a.T()), logger, a.cfg, a.level, shutdown.NewCoordinator(a.cfg, logger), nil, nil))
+}
@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"exampleproj/config"
+	"exampleproj/db"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/routers"
+	"exampleproj/routers/handlers"
+	"exampleproj/routers/schemas"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx"
+)
+
+type AuthHandlerTestSuite struct {
+	suite.Suite
+	r     *chi.Mux
+	store db.Store
+	fxApp *fx.App
+}
+
+func (a *AuthHandlerTestSuite) SetupSuite() {
+	a.fxApp = fx.New(
+		fx.Provide(config.NewViper),
+		fx.Provide(config.NewConfig),
+		fx.Provide(app.NewLogger),
+		fx.Provide(NewTestStore),
+		fx.Provide(NewTestRedis),
+		auth.Module,
+		fx.Provide(
+			fx.Annotate(
+				routers.NewRouter,
+				fx.ParamTags(`group:"handlers"`, `optional:"true"`, `optional:"true"`, `optional:"true"`, `optional:"true"`),
+			),
+			handlers.NewUserHandler,
+			handlers.NewAuthorHandler,
+			handlers.NewAuthHandler,
+			routers.AsRoute(handlers.NewAPI)),
+		fx.Invoke(func(r *chi.Mux, store db.Store) {
+			a.r = r
+			a.store = store
+		}),
+	)
+
+	if err := a.fxApp.Start(context.Background()); err != nil {
+		panic(err)
+	}
+
+	resp, _ := a.do("POST", "/users", `{
+	"name": "John Doe",
+	"email": "auth@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`, "")
+	a.Require().Equal(http.StatusCreated, resp.StatusCode)
+}
+
+func (a *AuthHandlerTestSuite) TearDownSuite() {
+	a.fxApp.Stop(context.Background())
+}
+
+func (a *AuthHandlerTestSuite) do(method, target, body, token string) (*http.Response, []byte) {
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	a.r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	content, _ := io.ReadAll(resp.Body)
+	return resp, content
+}
+
+func (a *AuthHandlerTestSuite) login() schemas.TokenResponse {
+	resp, content := a.do("POST", "/auth/login", `{"email": "auth@test.com", "password": "!@SDGsjfe"}`, "")
+	a.Require().Equal(200, resp.StatusCode)
+
+	var tokens schemas.TokenResponse
+	if err := json.Unmarshal(content, &tokens); err != nil {
+		panic(err)
+	}
+	return tokens
+}
+
+func (a *AuthHandlerTestSuite) errorCode(content []byte) int {
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	return errResp.Code
+}
+
+func (a *AuthHandlerTestSuite) TestLogin() {
+	tokens := a.login()
+	a.NotEmpty(tokens.AccessToken)
+	a.NotEmpty(tokens.RefreshToken)
+	a.Equal("Bearer", tokens.TokenType)
+	a.Greater(tokens.ExpiresIn, 0)
+}
+
+func (a *AuthHandlerTestSuite) TestLoginWithWrongPassword() {
+	for _, body := range []string{
+		`{"email": "auth@test.com", "password": "wrong password"}`,
+		`{"email": "nobody@test.com", "password": "!@SDGsjfe"}`,
+	} {
+		resp, content := a.do("POST", "/auth/login", body, "")
+		a.Equal(401, resp.StatusCode)
+		a.Equal(app.ErrorCodeInvalidCredentials, a.errorCode(content))
+	}
+}
+
+func (a *AuthHandlerTestSuite) TestRefreshRotatesTheToken() {
+	tokens := a.login()
+	body := fmt.Sprintf(`{"refresh_token": %q}`, tokens.RefreshToken)
+
+	resp, content := a.do("POST", "/auth/refresh", body, "")
+	a.Equal(200, resp.StatusCode)
+
+	var refreshed schemas.TokenResponse
+	json.Unmarshal(content, &refreshed)
+	a.NotEmpty(refreshed.AccessToken)
+	a.NotEqual(tokens.RefreshToken, refreshed.RefreshToken)
+
+	// the consumed token can't be replayed
+	resp, content = a.do("POST", "/auth/refresh", body, "")
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeInvalidToken, a.errorCode(content))
+}
+
+func (a *AuthHandlerTestSuite) TestDeletedUsersCantRefresh() {
+	resp, _ := a.do("POST", "/users", `{
+	"name": "Gone",
+	"email": "gone@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`, "")
+	a.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	resp, content := a.do("POST", "/auth/login", `{"email": "gone@test.com", "password": "!@SDGsjfe"}`, "")
+	a.Require().Equal(200, resp.StatusCode)
+	var tokens schemas.TokenResponse
+	a.Require().NoError(json.Unmarshal(content, &tokens))
+
+	user, err := a.store.GetUserByEmail(context.Background(), "gone@test.com")
+	a.Require().NoError(err)
+	a.Require().NoError(a.store.DeleteUser(context.Background(), user.ID))
+
+	resp, content = a.do("POST", "/auth/refresh", fmt.Sprintf(`{"refresh_token": %q}`, tokens.RefreshToken), "")
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeInvalidToken, a.errorCode(content))
+}
+
+func (a *AuthHandlerTestSuite) TestDefaultSecretRefusedOutsideLocal() {
+	cfg := config.NewConfig(config.NewViper(nil))
+	_, err := auth.NewAuthenticator(cfg, nil)
+	a.NoError(err)
+
+	cfg.App.Env = config.Prod
+	_, err = auth.NewAuthenticator(cfg, nil)
+	a.Error(err)
+
+	cfg.AUTH.SECRET = "a-real-secret"
+	_, err = auth.NewAuthenticator(cfg, nil)
+	a.NoError(err)
+
+	cfg.AUTH.SECRET = ""
+	cfg.App.Env = config.Local
+	_, err = auth.NewAuthenticator(cfg, nil)
+	a.Error(err)
+}
+
+func (a *AuthHandlerTestSuite) TestLogout() {
+	tokens := a.login()
+
+	resp, content := a.do("POST", "/auth/logout", "", "")
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeUnauthenticated, a.errorCode(content))
+
+	body := fmt.Sprintf(`{"refresh_token": %q}`, tokens.RefreshToken)
+	resp, _ = a.do("POST", "/auth/logout", body, tokens.AccessToken)
+	a.Equal(204, resp.StatusCode)
+
+	// both tokens are revoked
+	resp, content = a.do("POST", "/auth/logout", "", tokens.AccessToken)
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeInvalidToken, a.errorCode(content))
+
+	resp, _ = a.do("POST", "/auth/refresh", body, "")
+	a.Equal(401, resp.StatusCode)
+}
+
+func (a *AuthHandlerTestSuite) TestLogoutWithoutBody() {
+	tokens := a.login()
+
+	resp, _ := a.do("POST", "/auth/logout", "", tokens.AccessToken)
+	a.Equal(204, resp.StatusCode)
+
+	resp, content := a.do("POST", "/auth/logout", "", tokens.AccessToken)
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeInvalidToken, a.errorCode(content))
+}
+
+func (a *AuthHandlerTestSuite) TestInvalidToken() {
+	resp, content := a.do("POST", "/auth/logout", "", "not-a-jwt")
+	a.Equal(401, resp.StatusCode)
+	a.Equal(app.ErrorCodeInvalidToken, a.errorCode(content))
+}
+
+func TestAuthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthHandlerTestSuite))
+}
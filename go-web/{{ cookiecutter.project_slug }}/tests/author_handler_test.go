@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"exampleproj/internal/app"
+	"exampleproj/routers/schemas"
+	"fmt"
+)
+
+// the authors are served by the same API as the users, so their tests share
+// the UserHandlerTestSuite setup and helpers
+
+// createAuthor creates an author owned by the user of the token
+func (u *UserHandlerTestSuite) createAuthor(name, token string) schemas.Author {
+	resp, content := u.do("POST", "/authors", []byte(fmt.Sprintf(`{"name": %q, "bio": "writes"}`, name)), token)
+	u.Require().Equal(201, resp.StatusCode, string(content))
+
+	var author schemas.Author
+	if err := json.Unmarshal(content, &author); err != nil {
+		panic(err)
+	}
+	return author
+}
+
+func (u *UserHandlerTestSuite) TestAuthorCRUD() {
+	resp, _ := u.do("POST", "/authors", []byte(`{"name": "Ann Writer"}`))
+	u.Equal(401, resp.StatusCode)
+
+	token := u.login("writer@test.com", "!@SDGsjfe")
+	owner, err := u.store.GetUserByEmail(context.Background(), "writer@test.com")
+	u.Require().NoError(err)
+
+	author := u.createAuthor("Ann Writer", token)
+	u.Equal(owner.ID, author.UserId)
+	u.Require().NotNil(author.Bio)
+	u.Equal("writes", *author.Bio)
+
+	target := fmt.Sprintf("/authors/%d", author.Id)
+	resp, content := u.do("GET", target, nil)
+	u.Equal(200, resp.StatusCode)
+	u.JSONEq(fmt.Sprintf(`{"id": %d, "name": "Ann Writer", "bio": "writes", "user_id": %d}`, author.Id, owner.ID), string(content))
+
+	// an empty bio clears it, the name is left unchanged
+	resp, content = u.do("PATCH", target, []byte(`{"bio": ""}`), token)
+	u.Equal(200, resp.StatusCode)
+	u.JSONEq(fmt.Sprintf(`{"id": %d, "name": "Ann Writer", "user_id": %d}`, author.Id, owner.ID), string(content))
+
+	resp, content = u.do("GET", "/authors?name=Ann%20W", nil)
+	u.Equal(200, resp.StatusCode)
+
+	var authors schemas.AuthorPage
+	if err := json.Unmarshal(content, &authors); err != nil {
+		panic(err)
+	}
+	u.Len(authors.Items, 1)
+
+	resp, _ = u.do("DELETE", target, nil, token)
+	u.Equal(204, resp.StatusCode)
+
+	resp, content = u.do("GET", target, nil)
+	u.Equal(404, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeAuthorNotFound, errResp.Code)
+}
+
+func (u *UserHandlerTestSuite) TestAuthorOwnership() {
+	ownerToken := u.login("author-owner@test.com", "!@SDGsjfe")
+	author := u.createAuthor("Owned Author", ownerToken)
+	target := fmt.Sprintf("/authors/%d", author.Id)
+
+	otherToken := u.login("author-other@test.com", "!@SDGsjfe")
+	for _, method := range []string{"PATCH", "DELETE"} {
+		resp, content := u.do(method, target, []byte(`{"name": "pwned"}`), otherToken)
+		u.Equal(403, resp.StatusCode, method)
+
+		var errResp app.MyError
+		json.Unmarshal(content, &errResp)
+		u.Equal(app.ErrorCodeForbidden, errResp.Code, method)
+	}
+
+	// the admin role is granted the authors permissions
+	adminToken := u.login("author-admin@test.com", "!@SDGsjfe")
+	u.grantRole("author-admin@test.com", "admin")
+
+	resp, content := u.do("PATCH", target, []byte(`{"name": "Moderated"}`), adminToken)
+	u.Equal(200, resp.StatusCode)
+	u.Contains(string(content), "Moderated")
+
+	resp, _ = u.do("DELETE", target, nil, adminToken)
+	u.Equal(204, resp.StatusCode)
+}
+
+func (u *UserHandlerTestSuite) TestAuthorsDeletedWithTheirUser() {
+	token := u.login("author-leaving@test.com", "!@SDGsjfe")
+	author := u.createAuthor("Leaving Author", token)
+
+	user, err := u.store.GetUserByEmail(context.Background(), "author-leaving@test.com")
+	u.Require().NoError(err)
+	u.Require().NoError(u.store.DeleteUser(context.Background(), user.ID))
+
+	resp, _ := u.do("GET", fmt.Sprintf("/authors/%d", author.Id), nil)
+	u.Equal(404, resp.StatusCode)
+}
+
+func (u *UserHandlerTestSuite) TestAuthorNotFound() {
+	token := u.login("author-notfound@test.com", "!@SDGsjfe")
+
+	for _, method := range []string{"GET", "PATCH", "DELETE"} {
+		resp, content := u.do(method, "/authors/999999", []byte(`{"name": "nobody"}`), token)
+		u.Equal(404, resp.StatusCode, method)
+
+		var errResp app.MyError
+		json.Unmarshal(content, &errResp)
+		u.Equal(app.ErrorCodeAuthorNotFound, errResp.Code, method)
+	}
+}
+
+func (u *UserHandlerTestSuite) TestListAuthorsPagination() {
+	token := u.login("author-pages@test.com", "!@SDGsjfe")
+	for _, name := range []string{"Page Author C", "Page Author A", "Page Author B"} {
+		u.createAuthor(name, token)
+	}
+
+	var names []string
+	target := "/authors?limit=2&name=Page%20Author"
+	for target != "" {
+		resp, content := u.do("GET", target, nil)
+		u.Require().Equal(200, resp.StatusCode)
+
+		var page schemas.AuthorPage
+		if err := json.Unmarshal(content, &page); err != nil {
+			panic(err)
+		}
+		for _, author := range page.Items {
+			names = append(names, author.Name)
+		}
+
+		target = ""
+		if page.NextCursor != nil {
+			u.NotEmpty(resp.Header.Get("Link"))
+			target = "/authors?limit=2&name=Page%20Author&cursor=" + *page.NextCursor
+		}
+	}
+	u.Equal([]string{"Page Author A", "Page Author B", "Page Author C"}, names)
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+
+	"exampleproj/config"
+	"exampleproj/db"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/fx"
+)
+
+// NewTestStore provides the db.Store for the test suites.
+//
+// With DB_ENGINE=postgres a throwaway database is created and migrated with
+// atlas, otherwise an in-memory sqlite is used so no container is required.
+func NewTestStore(lc fx.Lifecycle, cfg *config.Config) (db.Store, error) {
+	if cfg.DB.ENGINE != config.Postgres {
+		cfg.DB.ENGINE = config.SQLite
+		cfg.DB.NAME = ":memory:"
+		return db.NewStore(lc, cfg)
+	}
+
+	// temporarily hardcode the test database name
+	testDB := "test_db"
+	ctx := context.Background()
+	cfg.DB.NAME = "postgres"
+
+	masterConn, err := pgx.Connect(ctx, db.GetPostgresqlDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = masterConn.Exec(ctx, "create database "+testDB); err != nil {
+		return nil, err
+	}
+
+	cfg.DB.NAME = testDB
+
+	// registered before the store so it runs after the pool is closed
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if _, err := masterConn.Exec(ctx, "drop database "+testDB); err != nil {
+				return err
+			}
+
+			return masterConn.Close(ctx)
+		},
+	})
+
+	store, err := db.NewStore(lc, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := fmt.Sprintf("%s?search_path=public&sslmode=disable", db.GetPostgresqlDSN(cfg))
+	if _, err := db.Migrate(dsn); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"exampleproj/config"
+	"exampleproj/routers/handlers"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type DocsHandlerTestSuite struct {
+	suite.Suite
+	docs handlers.Docs
+}
+
+func TestDocsHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DocsHandlerTestSuite))
+}
+
+func (d *DocsHandlerTestSuite) SetupSuite() {
+	openAPI, err := os.ReadFile("../swagger.yml")
+	d.Require().NoError(err)
+	asyncAPI, err := os.ReadFile("../asyncapi.yaml")
+	d.Require().NoError(err)
+	d.docs = handlers.Docs{OpenAPI: openAPI, AsyncAPI: asyncAPI}
+}
+
+func (d *DocsHandlerTestSuite) router(env config.Env) *chi.Mux {
+	cfg := &config.Config{}
+	cfg.App.Env = env
+
+	h, err := handlers.NewDocsHandler(cfg, zap.NewNop().Sugar(), d.docs)
+	d.Require().NoError(err)
+
+	r := chi.NewRouter()
+	h.RegisterRoute(r)
+	return r
+}
+
+func (d *DocsHandlerTestSuite) get(r *chi.Mux, path string) (*http.Response, string) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func (d *DocsHandlerTestSuite) TestDocuments() {
+	r := d.router(config.Local)
+
+	resp, body := d.get(r, "/openapi.yaml")
+	d.Equal(http.StatusOK, resp.StatusCode)
+	d.Equal("application/yaml", resp.Header.Get("Content-Type"))
+	d.Equal(string(d.docs.OpenAPI), body)
+
+	resp, body = d.get(r, "/asyncapi.yaml")
+	d.Equal(http.StatusOK, resp.StatusCode)
+	d.Equal(string(d.docs.AsyncAPI), body)
+}
+
+func (d *DocsHandlerTestSuite) TestSwaggerUI() {
+	r := d.router(config.Local)
+
+	resp, _ := d.get(r, "/docs")
+	d.Equal(http.StatusMovedPermanently, resp.StatusCode)
+	d.Equal("/docs/openapi/", resp.Header.Get("Location"))
+
+	resp, body := d.get(r, "/docs/openapi/")
+	d.Equal(http.StatusOK, resp.StatusCode)
+	d.Contains(body, "swagger-ui")
+}
+
+func (d *DocsHandlerTestSuite) TestAsyncAPIView() {
+	resp, body := d.get(d.router(config.Local), "/docs/asyncapi")
+	d.Equal(http.StatusOK, resp.StatusCode)
+	d.Contains(resp.Header.Get("Content-Type"), "text/html")
+	d.Contains(body, "pingRequest")
+	d.Contains(body, `id="message-pong"`)
+}
+
+func (d *DocsHandlerTestSuite) TestDisabledInProd() {
+	r := d.router(config.Prod)
+
+	for _, path := range []string{"/openapi.yaml", "/asyncapi.yaml", "/docs", "/docs/openapi/", "/docs/asyncapi"} {
+		resp, _ := d.get(r, path)
+		d.Equal(http.StatusNotFound, resp.StatusCode, path)
+	}
+}
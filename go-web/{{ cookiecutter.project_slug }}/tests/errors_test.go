@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"exampleproj/internal/app"
+	"exampleproj/routers/schemas"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorCatalogTestSuite struct {
+	suite.Suite
+}
+
+func TestErrorCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorCatalogTestSuite))
+}
+
+func (e *ErrorCatalogTestSuite) TestMapping() {
+	cases := []struct {
+		err    error
+		code   int
+		status int
+	}{
+		{pgx.ErrNoRows, app.ErrorCodeNotFound, http.StatusNotFound},
+		{fmt.Errorf("get user: %w", pgx.ErrNoRows), app.ErrorCodeNotFound, http.StatusNotFound},
+		{&pgconn.PgError{Code: "23505"}, app.ErrorCodeConflict, http.StatusConflict},
+		{context.DeadlineExceeded, app.ErrorCodeUnavailable, http.StatusServiceUnavailable},
+		{errors.New("connection refused"), app.ErrorCodeUnknown, http.StatusInternalServerError},
+		{app.NewMyError(errors.New("no"), app.ErrorCodeForbidden), app.ErrorCodeForbidden, http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		merr := app.AsMyError(c.err)
+		e.Equal(c.code, merr.Code, c.err.Error())
+		e.Equal(c.status, merr.HTTPStatus(), c.err.Error())
+	}
+}
+
+func (e *ErrorCatalogTestSuite) TestInternalErrorsHideCause() {
+	w := httptest.NewRecorder()
+	app.RenderError(w, httptest.NewRequest("GET", "/", nil), errors.New("dial tcp 10.0.0.1:5432: connection refused"))
+
+	e.Equal(http.StatusInternalServerError, w.Code)
+	e.Equal("application/json", w.Header().Get("Content-Type"))
+	e.NotContains(w.Body.String(), "10.0.0.1")
+}
+
+func (e *ErrorCatalogTestSuite) TestUnknownCodeFallsBack() {
+	def := app.LookupError(424242)
+	e.Equal(http.StatusInternalServerError, def.HTTPStatus)
+	e.Equal(app.CategoryInternal, def.Category)
+}
+
+func (e *ErrorCatalogTestSuite) TestProblemFromAcceptHeader() {
+	r := httptest.NewRequest("GET", "/users/42", nil)
+	r.Header.Set("Accept", "application/json;q=0.5, application/problem+json")
+	w := httptest.NewRecorder()
+	app.RenderError(w, r, app.NewMyError(pgx.ErrNoRows, app.ErrorCodeUserNotFound))
+
+	e.Equal(http.StatusNotFound, w.Code)
+	e.Equal("application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem schemas.Problem
+	e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &problem))
+	e.Equal("urn:exampleproj:error:1001", problem.Type)
+	e.Equal("user not found", problem.Title)
+	e.Equal(http.StatusNotFound, problem.Status)
+	e.Equal(app.ErrorCodeUserNotFound, problem.Code)
+	e.Require().NotNil(problem.Instance)
+	e.Equal("/users/42", *problem.Instance)
+	e.Require().NotNil(problem.Detail)
+	e.Equal("user not found", *problem.Detail)
+}
+
+func (e *ErrorCatalogTestSuite) TestErrorFormatNegotiation() {
+	render := func(format app.ErrorFormat, accept string) string {
+		var got app.ErrorFormat
+		h := app.ErrorFormatMiddleware(format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = app.NegotiateErrorFormat(r)
+		}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		return string(got)
+	}
+
+	e.Equal("basic", render(app.ErrorFormatBasic, ""))
+	e.Equal("problem", render(app.ErrorFormatProblem, ""))
+	e.Equal("problem", render(app.ErrorFormatProblem, "*/*"))
+	e.Equal("basic", render(app.ErrorFormatProblem, "application/json"))
+	e.Equal("problem", render(app.ErrorFormatBasic, "application/problem+json"))
+	e.Equal("basic", render(app.ErrorFormatBasic, "application/problem+json;q=0.1, application/json"))
+	e.Equal("basic", render(app.ErrorFormatBasic, "application/problem+json;q=0"))
+}
+
+func (e *ErrorCatalogTestSuite) TestProblemCarriesFieldErrors() {
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	r := httptest.NewRequest("POST", "/users", nil)
+	r.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+	app.RenderError(w, r, app.Validator().Struct(request{Email: "nope"}))
+
+	var problem schemas.Problem
+	e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &problem))
+	e.Equal(app.ErrorCodeValidation, problem.Code)
+	e.Require().NotNil(problem.Errors)
+	e.Equal("email", (*problem.Errors)[0].Field)
+}
+
+func (e *ErrorCatalogTestSuite) TestTranslatorFromAcceptLanguage() {
+	cases := map[string]string{
+		"":                        "en",
+		"zh-CN,zh;q=0.9,en;q=0.8": "zh",
+		"zh-TW":                   "zh",
+		"fr-FR, en;q=0.5":         "en",
+		"fr":                      "en",
+		"en;q=0.5, zh":            "zh",
+		"not a language tag!!":    "en",
+	}
+
+	for header, locale := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", header)
+		e.Equal(locale, app.TranslatorFromRequest(r).Locale(), header)
+	}
+}
+
+func (e *ErrorCatalogTestSuite) TestLocalizedMessages() {
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	r := httptest.NewRequest("POST", "/users", nil)
+	r.Header.Set("Accept-Language", "zh-CN")
+	w := httptest.NewRecorder()
+	app.RenderError(w, r, app.Validator().Struct(request{Email: "nope"}))
+
+	var errResp app.MyError
+	e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+	e.Equal("参数校验失败", errResp.Message)
+	e.Require().NotNil(errResp.Errors)
+	e.Equal("email必须是一个有效的邮箱", (*errResp.Errors)[0].Message)
+
+	w = httptest.NewRecorder()
+	app.RenderError(w, httptest.NewRequest("GET", "/", nil), app.Validator().Struct(request{Email: "nope"}))
+
+	e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+	e.Equal("validation failed", errResp.Message)
+	e.Equal("email must be a valid email address", (*errResp.Errors)[0].Message)
+}
+
+func (e *ErrorCatalogTestSuite) TestLocalizedCause() {
+	r := httptest.NewRequest("GET", "/users/42", nil)
+	r.Header.Set("Accept-Language", "zh")
+	w := httptest.NewRecorder()
+	app.RenderError(w, r, app.NewMyError(pgx.ErrNoRows, app.ErrorCodeUserNotFound))
+
+	var errResp app.MyError
+	e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+	e.Equal("用户不存在", errResp.Message)
+}
+
+func (e *ErrorCatalogTestSuite) TestOnlyPublicCausesShown() {
+	render := func(err error) string {
+		w := httptest.NewRecorder()
+		app.RenderError(w, httptest.NewRequest("POST", "/users", nil), err)
+		var errResp app.MyError
+		e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		return errResp.Message
+	}
+
+	unique := &pgconn.PgError{Code: "23505", Message: "duplicate key value", ConstraintName: "users_email_key"}
+	e.Equal("resource already exists", render(unique))
+	e.Equal("email already registered", render(app.NewMyError(unique, app.ErrorCodeEmailExists)))
+	e.Equal("resource not found", render(fmt.Errorf("get user: %w", pgx.ErrNoRows)))
+	e.Equal("invalid password: passwords do not match",
+		render(app.NewMyError(app.Public(errors.New("passwords do not match")), app.ErrorCodeInvalidPassword)))
+}
+
+func (e *ErrorCatalogTestSuite) TestDecodeError() {
+	message := func(err error) string {
+		w := httptest.NewRecorder()
+		app.RenderError(w, httptest.NewRequest("POST", "/users", nil), app.DecodeError(err))
+		e.Equal(http.StatusBadRequest, w.Code)
+		var errResp app.MyError
+		e.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		e.Equal(app.ErrorCodeInvalidBody, errResp.Code)
+		return errResp.Message
+	}
+
+	var body struct {
+		Age int32 `json:"age"`
+	}
+	typeErr := json.Unmarshal([]byte(`{"age": "ten"}`), &body)
+	e.Equal("invalid request body: field age has the wrong type", message(fmt.Errorf("can't decode JSON body: %w", typeErr)))
+	e.Equal("invalid request body: unexpected end of the body", message(io.ErrUnexpectedEOF))
+
+	syntaxErr := json.Unmarshal([]byte(`{"age"`), &body)
+	e.Contains(message(syntaxErr), "invalid request body: ")
+
+	// an EOF out of a request body is the failure of a dependency
+	merr := app.AsMyError(fmt.Errorf("read redis reply: %w", io.EOF))
+	e.Equal(app.ErrorCodeUnknown, merr.Code)
+	e.Equal(http.StatusInternalServerError, merr.HTTPStatus())
+}
+
+func (e *ErrorCatalogTestSuite) TestRenderedErrorIsKeptForTheLog() {
+	ctx, renderedError := app.WithRenderedError(context.Background())
+	r := httptest.NewRequest("GET", "/users/42", nil).WithContext(ctx)
+	app.RenderError(httptest.NewRecorder(), r, fmt.Errorf("get user: %w", pgx.ErrNoRows))
+
+	e.ErrorIs(renderedError(), pgx.ErrNoRows)
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"exampleproj/cache"
+	"exampleproj/config"
+	"exampleproj/internal/health"
+	"exampleproj/routers/handlers"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthTestSuite struct {
+	suite.Suite
+	cfg *config.Config
+	// probes counts the runs of the counted check
+	probes atomic.Int32
+}
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (h *HealthTestSuite) SetupTest() {
+	h.cfg = config.NewConfig(config.NewViper(nil))
+	h.cfg.HEALTH.TIMEOUT = 50 * time.Millisecond
+	h.cfg.HEALTH.CACHE_TTL = 0
+	h.probes.Store(0)
+}
+
+func (h *HealthTestSuite) check(name string, err error) health.Check {
+	return health.Check{
+		Name: name,
+		Probe: func(ctx context.Context) error {
+			h.probes.Add(1)
+			return err
+		},
+	}
+}
+
+// router serves the probes of registry
+func (h *HealthTestSuite) router(registry *health.Registry) *chi.Mux {
+	r := chi.NewRouter()
+	handlers.NewHealthHandler(registry).RegisterRoute(r)
+	return r
+}
+
+func (h *HealthTestSuite) get(r *chi.Mux, path string) (*http.Response, health.Report) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+	var report health.Report
+	h.Require().NoError(json.NewDecoder(w.Body).Decode(&report))
+	return w.Result(), report
+}
+
+func (h *HealthTestSuite) TestReady() {
+	r := h.router(health.NewRegistry(h.cfg, []health.Check{
+		h.check("database", nil),
+		h.check("redis", nil),
+	}))
+
+	resp, report := h.get(r, "/readyz")
+	h.Equal(http.StatusOK, resp.StatusCode)
+	h.Equal("application/json", resp.Header.Get("Content-Type"))
+	h.Equal(health.StatusOK, report.Status)
+	h.Len(report.Checks, 2)
+	h.Equal(health.StatusOK, report.Checks["database"].Status)
+	h.NotEmpty(report.Checks["database"].Duration)
+}
+
+func (h *HealthTestSuite) TestRequiredCheckFails() {
+	r := h.router(health.NewRegistry(h.cfg, []health.Check{
+		h.check("database", nil),
+		h.check("redis", errors.New("connection refused")),
+	}))
+
+	resp, report := h.get(r, "/readyz")
+	h.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	h.Equal(health.StatusFail, report.Status)
+	h.Equal(health.StatusOK, report.Checks["database"].Status)
+	h.Equal(health.StatusFail, report.Checks["redis"].Status)
+	h.Equal("connection refused", report.Checks["redis"].Error)
+
+	// the liveness doesn't depend on the checks
+	resp, report = h.get(r, "/livez")
+	h.Equal(http.StatusOK, resp.StatusCode)
+	h.Equal(health.StatusOK, report.Status)
+	h.Empty(report.Checks)
+}
+
+func (h *HealthTestSuite) TestOptionalCheckFails() {
+	pyth := h.check("pyth", errors.New("unreachable"))
+	pyth.Optional = true
+	r := h.router(health.NewRegistry(h.cfg, []health.Check{h.check("database", nil), pyth}))
+
+	resp, report := h.get(r, "/readyz")
+	h.Equal(http.StatusOK, resp.StatusCode)
+	h.Equal(health.StatusFail, report.Checks["pyth"].Status)
+	h.True(report.Checks["pyth"].Optional)
+}
+
+func (h *HealthTestSuite) TestTimeout() {
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	r := h.router(health.NewRegistry(h.cfg, []health.Check{
+		{
+			Name: "ignores-context",
+			Probe: func(ctx context.Context) error {
+				<-blocked
+				return nil
+			},
+		},
+		{
+			Name:    "own-timeout",
+			Timeout: 10 * time.Millisecond,
+			Probe: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}))
+
+	start := time.Now()
+	resp, report := h.get(r, "/readyz")
+	h.Less(time.Since(start), time.Second)
+	h.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	h.Equal("timed out after 50ms", report.Checks["ignores-context"].Error)
+	h.Equal("timed out after 10ms", report.Checks["own-timeout"].Error)
+}
+
+func (h *HealthTestSuite) TestCachedResults() {
+	h.cfg.HEALTH.CACHE_TTL = time.Minute
+	registry := health.NewRegistry(h.cfg, []health.Check{h.check("database", nil)})
+
+	first := registry.Readiness(context.Background())
+	second := registry.Readiness(context.Background())
+	h.EqualValues(1, h.probes.Load())
+	h.Equal(first.CheckedAt, second.CheckedAt)
+
+	// a canceled probe doesn't fail the shared results
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.cfg.HEALTH.CACHE_TTL = 0
+	registry = health.NewRegistry(h.cfg, []health.Check{
+		{Name: "database", Probe: func(ctx context.Context) error { return ctx.Err() }},
+	})
+	h.True(registry.Readiness(ctx).OK())
+}
+
+func (h *HealthTestSuite) TestShutdown() {
+	registry := health.NewRegistry(h.cfg, []health.Check{h.check("database", nil)})
+	r := h.router(registry)
+
+	resp, _ := h.get(r, "/readyz")
+	h.Equal(http.StatusOK, resp.StatusCode)
+
+	registry.Shutdown()
+	resp, report := h.get(r, "/readyz")
+	h.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	h.Equal(health.StatusShuttingDown, report.Status)
+
+	// the pending requests are still served
+	resp, _ = h.get(r, "/livez")
+	h.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (h *HealthTestSuite) TestRedisCheck() {
+	mr := miniredis.RunT(h.T())
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	check := cache.NewHealthCheck(rdb)
+	h.Equal("redis", check.Name)
+	h.NoError(check.Probe(context.Background()))
+
+	mr.Close()
+	h.Error(check.Probe(context.Background()))
+}
@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/shutdown"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+type HTTPServerTestSuite struct {
+	suite.Suite
+	cfg *config.Config
+	dir string
+}
+
+func TestHTTPServerTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPServerTestSuite))
+}
+
+func (h *HTTPServerTestSuite) SetupTest() {
+	h.cfg = config.NewConfig(config.NewViper(nil))
+	h.cfg.App.Addr = "127.0.0.1"
+	h.cfg.App.Port = freePort(h.T())
+	h.dir = h.T().TempDir()
+}
+
+// freePort is a tcp port nothing listens on
+func freePort(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return fmt.Sprint(ln.Addr().(*net.TCPAddr).Port)
+}
+
+// writeCert writes a self-signed certificate of localhost to the cert and
+// key files of h.cfg, it's returned for the clients to trust it
+func (h *HTTPServerTestSuite) writeCert(name string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	h.Require().NoError(err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	h.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	h.Require().NoError(err)
+	cert, err := x509.ParseCertificate(der)
+	h.Require().NoError(err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	h.Require().NoError(err)
+
+	h.cfg.App.TLS.CERT_FILE = filepath.Join(h.dir, "tls.crt")
+	h.cfg.App.TLS.KEY_FILE = filepath.Join(h.dir, "tls.key")
+	h.Require().NoError(os.WriteFile(h.cfg.App.TLS.CERT_FILE, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	h.Require().NoError(os.WriteFile(h.cfg.App.TLS.KEY_FILE, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return cert
+}
+
+// start serves a /proto route answering the protocol of the request
+func (h *HTTPServerTestSuite) start() *http.Server {
+	r := chi.NewRouter()
+	r.Get("/proto", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	lc := fxtest.NewLifecycle(h.T())
+	logger := zap.NewNop().Sugar()
+	coordinator := shutdown.NewCoordinator(h.cfg, logger)
+	server, err := app.NewHTTPServer(lc, logger, h.cfg, r, coordinator, nil)
+	h.Require().NoError(err)
+	lc.RequireStart()
+	h.T().Cleanup(func() {
+		coordinator.Shutdown(context.Background())
+		lc.RequireStop()
+	})
+	return server
+}
+
+func (h *HTTPServerTestSuite) get(client *http.Client, url string) (*http.Response, string) {
+	resp, err := client.Get(url)
+	h.Require().NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	h.Require().NoError(err)
+	return resp, string(body)
+}
+
+func (h *HTTPServerTestSuite) tlsClient(roots ...*x509.Certificate) *http.Client {
+	pool := x509.NewCertPool()
+	for _, cert := range roots {
+		pool.AddCert(cert)
+	}
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+		ForceAttemptHTTP2: true,
+		// a new connection per request, ex. to see the reloaded certificate
+		DisableKeepAlives: true,
+	}}
+}
+
+func (h *HTTPServerTestSuite) TestTimeouts() {
+	h.cfg.App.READ_HEADER_TIMEOUT = 3 * time.Second
+	h.cfg.App.MAX_HEADER_BYTES = 4096
+	server := h.start()
+
+	h.Equal(h.cfg.App.READ_TIMEOUT, server.ReadTimeout)
+	h.Equal(3*time.Second, server.ReadHeaderTimeout)
+	h.Equal(h.cfg.App.WRITE_TIMEOUT, server.WriteTimeout)
+	h.Equal(h.cfg.App.IDLE_TIMEOUT, server.IdleTimeout)
+	h.Equal(4096, server.MaxHeaderBytes)
+
+	// past MaxHeaderBytes, plus the 4096 bytes of slack of net/http
+	req, err := http.NewRequest("GET", "http://"+server.Addr+"/proto", nil)
+	h.Require().NoError(err)
+	req.Header.Set("X-Large", fmt.Sprintf("%010000d", 0))
+	resp, err := http.DefaultClient.Do(req)
+	h.Require().NoError(err)
+	resp.Body.Close()
+	h.Equal(http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+}
+
+func (h *HTTPServerTestSuite) TestHTTP1() {
+	server := h.start()
+	_, proto := h.get(http.DefaultClient, "http://"+server.Addr+"/proto")
+	h.Equal("HTTP/1.1", proto)
+}
+
+func (h *HTTPServerTestSuite) TestTLSWithHTTP2() {
+	cert := h.writeCert("first")
+	server := h.start()
+
+	resp, proto := h.get(h.tlsClient(cert), "https://"+server.Addr+"/proto")
+	h.Equal("HTTP/2.0", proto)
+	h.Equal("first", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// plain http isn't served
+	resp, _ = h.get(http.DefaultClient, "http://"+server.Addr+"/proto")
+	h.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (h *HTTPServerTestSuite) TestCertificateReload() {
+	h.cfg.App.TLS.RELOAD_INTERVAL = 0
+	first := h.writeCert("first")
+	server := h.start()
+	url := "https://" + server.Addr + "/proto"
+
+	resp, _ := h.get(h.tlsClient(first), url)
+	h.Equal("first", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	second := h.writeCert("second")
+	// the files may be written within the mtime resolution
+	future := time.Now().Add(time.Minute)
+	h.Require().NoError(os.Chtimes(h.cfg.App.TLS.CERT_FILE, future, future))
+
+	resp, _ = h.get(h.tlsClient(second), url)
+	h.Equal("second", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// a broken file keeps the current certificate
+	h.Require().NoError(os.WriteFile(h.cfg.App.TLS.CERT_FILE, []byte("garbage"), 0o600))
+	future = future.Add(time.Minute)
+	h.Require().NoError(os.Chtimes(h.cfg.App.TLS.CERT_FILE, future, future))
+
+	resp, _ = h.get(h.tlsClient(second), url)
+	h.Equal("second", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func (h *HTTPServerTestSuite) TestInvalidCertificate() {
+	h.cfg.App.TLS.CERT_FILE = filepath.Join(h.dir, "missing.crt")
+	h.cfg.App.TLS.KEY_FILE = filepath.Join(h.dir, "missing.key")
+
+	lc := fxtest.NewLifecycle(h.T())
+	logger := zap.NewNop().Sugar()
+	_, err := app.NewHTTPServer(lc, logger, h.cfg, chi.NewRouter(), shutdown.NewCoordinator(h.cfg, logger), nil)
+	h.Error(err)
+}
+
+func (h *HTTPServerTestSuite) TestH2C() {
+	h.cfg.App.H2C = true
+	server := h.start()
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}}
+	_, proto := h.get(client, "http://"+server.Addr+"/proto")
+	h.Equal("HTTP/2.0", proto)
+
+	// the HTTP/1 clients are still served
+	_, proto = h.get(http.DefaultClient, "http://"+server.Addr+"/proto")
+	h.Equal("HTTP/1.1", proto)
+}
+
+func (h *HTTPServerTestSuite) TestUnixSocket() {
+	h.cfg.App.SOCKET = filepath.Join(h.dir, "api.sock")
+	// a stale socket of a previous run
+	stale, err := net.Listen("unix", h.cfg.App.SOCKET)
+	h.Require().NoError(err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	h.start()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", h.cfg.App.SOCKET)
+		},
+	}}
+	_, proto := h.get(client, "http://unix/proto")
+	h.Equal("HTTP/1.1", proto)
+}
+
+func (h *HTTPServerTestSuite) TestSocketPathTaken() {
+	h.cfg.App.SOCKET = filepath.Join(h.dir, "api.sock")
+	h.Require().NoError(os.WriteFile(h.cfg.App.SOCKET, nil, 0o600))
+
+	lc := fxtest.NewLifecycle(h.T())
+	logger := zap.NewNop().Sugar()
+	_, err := app.NewHTTPServer(lc, logger, h.cfg, chi.NewRouter(), shutdown.NewCoordinator(h.cfg, logger), nil)
+	h.Require().NoError(err)
+	h.Error(lc.Start(context.Background()))
+}
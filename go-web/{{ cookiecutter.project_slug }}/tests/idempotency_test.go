@@ -0,0 +1,254 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/idempotency"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	mr            *miniredis.Miniredis
+	rdb           *redis.Client
+	authenticator *auth.Authenticator
+	r             *chi.Mux
+	// calls counts the requests which reached the handlers
+	calls atomic.Int32
+	// release unblocks /slow
+	release chan struct{}
+}
+
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+func (i *IdempotencyTestSuite) SetupTest() {
+	i.mr = miniredis.RunT(i.T())
+	i.rdb = redis.NewClient(&redis.Options{Addr: i.mr.Addr()})
+	i.calls.Store(0)
+	i.release = make(chan struct{})
+
+	cfg := config.NewConfig(config.NewViper(nil))
+	authenticator, err := auth.NewAuthenticator(cfg, i.rdb)
+	i.Require().NoError(err)
+	i.authenticator = authenticator
+	cfg.IDEMPOTENCY.ROUTES = []string{"/items", "/json", "/fail", "/slow"}
+	guard, err := idempotency.NewGuard(cfg, i.rdb, zap.NewNop().Sugar())
+	i.Require().NoError(err)
+
+	i.r = chi.NewRouter()
+	i.r.Use(i.authenticator.Middleware, guard.Middleware)
+	i.r.Post("/items", func(w http.ResponseWriter, r *http.Request) {
+		n := i.calls.Add(1)
+		w.Header().Set("Location", "/items/1")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]int32{"call": n})
+	})
+	i.r.Post("/json", func(w http.ResponseWriter, r *http.Request) {
+		n := i.calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]int32{"call": n})
+	})
+	i.r.Post("/fail", func(w http.ResponseWriter, r *http.Request) {
+		i.calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	i.r.Post("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-i.release
+		w.WriteHeader(http.StatusNoContent)
+	})
+	i.r.Post("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		i.calls.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "secret"})
+	})
+}
+
+func (i *IdempotencyTestSuite) TearDownTest() {
+	i.rdb.Close()
+}
+
+func (i *IdempotencyTestSuite) post(path, key, body, token string) *http.Response {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	i.r.ServeHTTP(w, req)
+	return w.Result()
+}
+
+func (i *IdempotencyTestSuite) errorCode(resp *http.Response) int {
+	var errResp app.MyError
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	return errResp.Code
+}
+
+func (i *IdempotencyTestSuite) TestReplay() {
+	first := i.post("/items", "key-1", `{"a": 1}`, "")
+	retry := i.post("/items", "key-1", `{"a": 1}`, "")
+
+	i.Equal(http.StatusCreated, retry.StatusCode)
+	i.Equal("/items/1", retry.Header.Get("Location"))
+	i.Equal("true", retry.Header.Get(idempotency.HeaderReplayed))
+	i.Empty(first.Header.Get(idempotency.HeaderReplayed))
+
+	var a, b bytes.Buffer
+	a.ReadFrom(first.Body)
+	b.ReadFrom(retry.Body)
+	i.JSONEq(a.String(), b.String())
+	i.EqualValues(1, i.calls.Load())
+
+	// the ttl bounds the replays
+	i.mr.FastForward(25 * time.Hour)
+	i.Equal(http.StatusCreated, i.post("/items", "key-1", `{"a": 1}`, "").StatusCode)
+	i.EqualValues(2, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestKeyReusedWithAnotherRequest() {
+	i.post("/items", "key-1", `{"a": 1}`, "")
+
+	resp := i.post("/items", "key-1", `{"a": 2}`, "")
+	i.Equal(http.StatusConflict, resp.StatusCode)
+	i.Equal(app.ErrorCodeIdempotencyKeyReused, i.errorCode(resp))
+	i.EqualValues(1, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestWithoutKey() {
+	i.post("/items", "", `{"a": 1}`, "")
+	i.post("/items", "", `{"a": 1}`, "")
+	i.EqualValues(2, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestInvalidKey() {
+	resp := i.post("/items", strings.Repeat("k", 256), `{}`, "")
+	i.Equal(http.StatusBadRequest, resp.StatusCode)
+	i.Equal(app.ErrorCodeInvalidParam, i.errorCode(resp))
+}
+
+func (i *IdempotencyTestSuite) TestServerErrorsReleaseTheKey() {
+	i.Equal(http.StatusInternalServerError, i.post("/fail", "key-1", `{}`, "").StatusCode)
+	i.Equal(http.StatusInternalServerError, i.post("/fail", "key-1", `{}`, "").StatusCode)
+	i.EqualValues(2, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestRequestInProgress() {
+	done := make(chan *http.Response)
+	go func() {
+		done <- i.post("/slow", "key-1", `{}`, "")
+	}()
+
+	// wait for the first request to take the key
+	i.Eventually(func() bool {
+		return i.mr.Exists("idempotency:ip:192.0.2.1:key-1")
+	}, time.Second, time.Millisecond)
+
+	resp := i.post("/slow", "key-1", `{}`, "")
+	i.Equal(http.StatusConflict, resp.StatusCode)
+	i.Equal(app.ErrorCodeIdempotencyInProgress, i.errorCode(resp))
+
+	close(i.release)
+	i.Equal(http.StatusNoContent, (<-done).StatusCode)
+}
+
+func (i *IdempotencyTestSuite) TestKeysScopedToTheUser() {
+	ctx := context.Background()
+	alice, err := i.authenticator.Issue(ctx, auth.User{ID: 1, Email: "alice@test.com"})
+	i.Require().NoError(err)
+	bob, err := i.authenticator.Issue(ctx, auth.User{ID: 2, Email: "bob@test.com"})
+	i.Require().NoError(err)
+
+	i.post("/items", "key-1", `{}`, alice.AccessToken)
+	resp := i.post("/items", "key-1", `{}`, bob.AccessToken)
+	i.Empty(resp.Header.Get(idempotency.HeaderReplayed))
+	i.EqualValues(2, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestAnonymousKeysScopedToTheIP() {
+	post := func(remoteAddr string) *http.Response {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.HeaderKey, "key-1")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		i.r.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	post("198.51.100.1:1234")
+	i.Empty(post("198.51.100.2:1234").Header.Get(idempotency.HeaderReplayed))
+	i.Equal("true", post("198.51.100.1:4321").Header.Get(idempotency.HeaderReplayed))
+	i.EqualValues(2, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestOnlyTheConfiguredRoutes() {
+	// the tokens of the login are never kept in redis
+	i.Equal(http.StatusOK, i.post("/auth/login", "key-1", `{}`, "").StatusCode)
+	retry := i.post("/auth/login", "key-1", `{}`, "")
+	i.Empty(retry.Header.Get(idempotency.HeaderReplayed))
+	i.EqualValues(2, i.calls.Load())
+	i.Empty(i.mr.Keys())
+}
+
+func (i *IdempotencyTestSuite) TestInvalidRoute() {
+	cfg := config.NewConfig(config.NewViper(nil))
+	cfg.IDEMPOTENCY.ROUTES = []string{"users"}
+	_, err := idempotency.NewGuard(cfg, i.rdb, zap.NewNop().Sugar())
+	i.Error(err)
+}
+
+func (i *IdempotencyTestSuite) TestReplayCompressed() {
+	// the compressor runs outside of the guard, as in routers.Middlewares
+	h := middleware.Compress(5)(i.r)
+	post := func() (*http.Response, string) {
+		req := httptest.NewRequest("POST", "/json", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.HeaderKey, "key-1")
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		resp := w.Result()
+		i.Require().Equal("gzip", resp.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(resp.Body)
+		i.Require().NoError(err)
+		var body bytes.Buffer
+		_, err = body.ReadFrom(zr)
+		i.Require().NoError(err)
+		return resp, body.String()
+	}
+
+	first, a := post()
+	retry, b := post()
+	i.Empty(first.Header.Get(idempotency.HeaderReplayed))
+	i.Equal("true", retry.Header.Get(idempotency.HeaderReplayed))
+	i.Equal(http.StatusCreated, retry.StatusCode)
+	i.JSONEq(a, b)
+	i.EqualValues(1, i.calls.Load())
+}
+
+func (i *IdempotencyTestSuite) TestBodyTooLarge() {
+	resp := i.post("/items", "key-1", `{"a": "`+strings.Repeat("a", 1<<20)+`"}`, "")
+	i.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+	i.Equal(app.ErrorCodeInvalidBody, i.errorCode(resp))
+	i.EqualValues(0, i.calls.Load())
+}
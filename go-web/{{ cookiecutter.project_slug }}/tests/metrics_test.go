@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"exampleproj/config"
+	"exampleproj/internal/admin"
+	"exampleproj/internal/app"
+	"exampleproj/internal/metrics"
+	"exampleproj/internal/shutdown"
+	"exampleproj/internal/tasks"
+	"exampleproj/routers/handlers"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	cfg *config.Config
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (m *MetricsTestSuite) SetupTest() {
+	m.cfg = config.NewConfig(config.NewViper(nil))
+}
+
+func (m *MetricsTestSuite) TestHTTPLabelsTheRoutePattern() {
+	h := metrics.NewHTTP()
+
+	r := chi.NewRouter()
+	r.Use(h.Middleware)
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Route("/authors", func(r chi.Router) {
+		r.Post("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/users/1", nil),
+		httptest.NewRequest("GET", "/users/2", nil),
+		httptest.NewRequest("POST", "/authors/3", nil),
+		httptest.NewRequest("GET", "/nowhere/4", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expected := `
+# HELP exampleproj_http_requests_total The http requests by route, method and status.
+# TYPE exampleproj_http_requests_total counter
+exampleproj_http_requests_total{method="GET",route="/users/{id}",status="200"} 2
+exampleproj_http_requests_total{method="POST",route="/authors/{id}",status="201"} 1
+exampleproj_http_requests_total{method="GET",route="not_found",status="404"} 1
+`
+	m.NoError(testutil.CollectAndCompare(h, strings.NewReader(expected), "exampleproj_http_requests_total"))
+	m.Equal(3, testutil.CollectAndCount(h, "exampleproj_http_request_duration_seconds"))
+}
+
+func (m *MetricsTestSuite) TestTaskOutcomes() {
+	tm := tasks.NewTaskMetrics()
+	handler := tm.Middleware(asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		if string(t.Payload()) == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	}))
+
+	m.NoError(handler.ProcessTask(context.Background(), asynq.NewTask("email:send", []byte("ok"))))
+	m.NoError(handler.ProcessTask(context.Background(), asynq.NewTask("email:send", []byte("ok"))))
+	m.Error(handler.ProcessTask(context.Background(), asynq.NewTask("email:send", []byte("fail"))))
+
+	expected := `
+# HELP exampleproj_asynq_tasks_total The tasks processed by type and outcome, succeeded or failed.
+# TYPE exampleproj_asynq_tasks_total counter
+exampleproj_asynq_tasks_total{outcome="failed",type="email:send"} 1
+exampleproj_asynq_tasks_total{outcome="succeeded",type="email:send"} 2
+`
+	m.NoError(testutil.CollectAndCompare(tm, strings.NewReader(expected), "exampleproj_asynq_tasks_total"))
+}
+
+func (m *MetricsTestSuite) TestQueueCollectorIsDownWithoutRedis() {
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer inspector.Close()
+
+	expected := `
+# HELP exampleproj_asynq_up Whether the queues could be inspected.
+# TYPE exampleproj_asynq_up gauge
+exampleproj_asynq_up 0
+`
+	m.NoError(testutil.CollectAndCompare(tasks.NewQueueCollector(inspector), strings.NewReader(expected)))
+}
+
+func (m *MetricsTestSuite) TestHubCountsTheClients() {
+	lc := fxtest.NewLifecycle(m.T())
+	hub := app.NewHub()
+	ws := handlers.NewWebsocketHandler(lc, nil, shutdown.NewCoordinator(m.cfg, zap.NewNop().Sugar()), hub)
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	r := chi.NewRouter()
+	ws.RegisterRoute(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	m.Equal(0, hub.Stats().Clients)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	m.Require().NoError(err)
+	defer conn.Close()
+
+	m.Eventually(func() bool {
+		return hub.Stats().Clients == 1
+	}, time.Second, 10*time.Millisecond)
+
+	expected := `
+# HELP exampleproj_websocket_clients The websocket clients connected to the hub.
+# TYPE exampleproj_websocket_clients gauge
+exampleproj_websocket_clients 1
+# HELP exampleproj_websocket_dropped_messages_total The messages dropped by the hub, the buffer of the client was full.
+# TYPE exampleproj_websocket_dropped_messages_total counter
+exampleproj_websocket_dropped_messages_total 0
+`
+	m.NoError(testutil.CollectAndCompare(app.NewHubCollector(hub), strings.NewReader(expected)))
+}
+
+func (m *MetricsTestSuite) TestRegistryGathersTheCollectors() {
+	registry, err := metrics.NewRegistry([]prometheus.Collector{metrics.NewHTTP(), app.NewPythCollector()})
+	m.Require().NoError(err)
+
+	families, err := registry.Gather()
+	m.Require().NoError(err)
+	var names []string
+	for _, f := range families {
+		names = append(names, f.GetName())
+	}
+	m.Contains(names, "exampleproj_http_requests_in_flight")
+	m.Contains(names, "go_goroutines")
+
+	// a collector registered twice is a mistake of the wiring
+	_, err = metrics.NewRegistry([]prometheus.Collector{metrics.NewHTTP(), metrics.NewHTTP()})
+	m.Error(err)
+}
+
+func (m *MetricsTestSuite) TestAdminServesTheGatherer() {
+	registry, err := metrics.NewRegistry([]prometheus.Collector{metrics.NewHTTP()})
+	m.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	admin.NewHandler(m.cfg, zap.NewAtomicLevel(), registry, nil).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	m.Equal(http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	m.Require().NoError(err)
+	m.Contains(string(body), "exampleproj_http_requests_in_flight 0")
+}
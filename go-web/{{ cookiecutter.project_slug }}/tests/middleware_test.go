@@ -0,0 +1,271 @@
+package tests
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/routers"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	cfg  *config.Config
+	logs *observer.ObservedLogs
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
+
+func (m *MiddlewareTestSuite) SetupTest() {
+	m.cfg = config.NewConfig(config.NewViper(nil))
+}
+
+// router serves a few test routes behind the middlewares of m.cfg
+func (m *MiddlewareTestSuite) router() *chi.Mux {
+	core, logs := observer.New(zapcore.InfoLevel)
+	m.logs = logs
+
+	mws, err := routers.Middlewares(m.cfg, zap.New(core).Sugar())
+	m.Require().NoError(err)
+
+	r := chi.NewRouter()
+	r.Use(mws...)
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	r.Get("/panic/late", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		panic("boom")
+	})
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	r.Get("/slow/stream", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+			w.Write([]byte("done"))
+		}
+	})
+	r.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+		app.RenderError(w, r, fmt.Errorf("get user: %w", pgx.ErrNoRows))
+	})
+	r.Get("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": "` + strings.Repeat("a", 2048) + `"}`))
+	})
+	return r
+}
+
+func (m *MiddlewareTestSuite) serve(r *chi.Mux, req *http.Request) *http.Response {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Result()
+}
+
+func (m *MiddlewareTestSuite) TestRecoverRendersError() {
+	resp := m.serve(m.router(), httptest.NewRequest("GET", "/panic", nil))
+	defer resp.Body.Close()
+	m.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	var errResp app.MyError
+	m.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+	m.Equal(app.ErrorCodeUnknown, errResp.Code)
+	m.NotContains(errResp.Message, "boom")
+
+	panics := m.logs.FilterMessage("panic serving request").All()
+	m.Require().Len(panics, 1)
+	m.Equal("boom", panics[0].ContextMap()["panic"])
+
+	// the access log sees the 500 of the recovery
+	requests := m.logs.FilterMessage("request").All()
+	m.Require().Len(requests, 1)
+	m.EqualValues(http.StatusInternalServerError, requests[0].ContextMap()["status"])
+}
+
+func (m *MiddlewareTestSuite) TestRecoverKeepsTheSentResponse() {
+	resp := m.serve(m.router(), httptest.NewRequest("GET", "/panic/late", nil))
+	defer resp.Body.Close()
+	m.Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	m.Require().NoError(err)
+	m.Equal("partial", string(body))
+	m.Len(m.logs.FilterMessage("panic serving request").All(), 1)
+}
+
+func (m *MiddlewareTestSuite) TestRecoverSkipsUpgrades() {
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	resp := m.serve(m.router(), req)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	m.Require().NoError(err)
+	m.Empty(body)
+	m.Len(m.logs.FilterMessage("panic serving request").All(), 1)
+}
+
+func (m *MiddlewareTestSuite) TestAccessLog() {
+	req := httptest.NewRequest("GET", "/json", nil)
+	req.Header.Set("X-Request-Id", "req-1")
+	resp := m.serve(m.router(), req)
+	resp.Body.Close()
+
+	requests := m.logs.FilterMessage("request").All()
+	m.Require().Len(requests, 1)
+	fields := requests[0].ContextMap()
+	m.Equal("req-1", fields["request_id"])
+	m.Equal("/json", fields["path"])
+	m.EqualValues(http.StatusOK, fields["status"])
+	m.NotContains(fields, "error")
+}
+
+func (m *MiddlewareTestSuite) TestAccessLogKeepsTheHiddenCause() {
+	resp := m.serve(m.router(), httptest.NewRequest("GET", "/missing", nil))
+	body, _ := io.ReadAll(resp.Body)
+	m.Equal(http.StatusNotFound, resp.StatusCode)
+	m.NotContains(string(body), "no rows")
+
+	requests := m.logs.FilterMessage("request").All()
+	m.Require().Len(requests, 1)
+	m.Equal("get user: no rows in result set", requests[0].ContextMap()["error"])
+}
+
+func (m *MiddlewareTestSuite) TestDisabledMiddlewares() {
+	m.cfg.MIDDLEWARE.ACCESS_LOG = false
+	m.cfg.MIDDLEWARE.RECOVER = false
+	r := m.router()
+
+	m.Panics(func() {
+		m.serve(r, httptest.NewRequest("GET", "/panic", nil))
+	})
+	m.Zero(m.logs.Len())
+}
+
+func (m *MiddlewareTestSuite) TestTimeout() {
+	m.cfg.MIDDLEWARE.TIMEOUT = 10 * time.Millisecond
+	m.cfg.MIDDLEWARE.ROUTE_TIMEOUTS = []string{"/slow/stream=1s"}
+	r := m.router()
+
+	resp := m.serve(r, httptest.NewRequest("GET", "/slow", nil))
+	defer resp.Body.Close()
+	m.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+
+	var errResp app.MyError
+	m.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+	m.Equal(app.ErrorCodeUnavailable, errResp.Code)
+
+	// the route override outlasts the handler
+	resp = m.serve(r, httptest.NewRequest("GET", "/slow/stream", nil))
+	defer resp.Body.Close()
+	m.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (m *MiddlewareTestSuite) TestInvalidRouteTimeout() {
+	m.cfg.MIDDLEWARE.ROUTE_TIMEOUTS = []string{"/slow"}
+	_, err := routers.Middlewares(m.cfg, zap.NewNop().Sugar())
+	m.Error(err)
+}
+
+func (m *MiddlewareTestSuite) TestInvalidErrorFormat() {
+	m.cfg.App.ERROR_FORMAT = "xml"
+	_, err := routers.Middlewares(m.cfg, zap.NewNop().Sugar())
+	m.ErrorContains(err, `invalid error format "xml"`)
+}
+
+func (m *MiddlewareTestSuite) TestRealIP() {
+	m.cfg.MIDDLEWARE.TRUSTED_PROXIES = []string{"10.0.0.0/8", "192.168.1.1"}
+	r := m.router()
+	r.Get("/ip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.RemoteAddr))
+	})
+
+	ip := func(peer, forwardedFor, realIP string) string {
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = peer
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		if realIP != "" {
+			req.Header.Set("X-Real-IP", realIP)
+		}
+		resp := m.serve(r, req)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	// the headers of the clients are ignored
+	m.Equal("203.0.113.9:1234", ip("203.0.113.9:1234", "1.2.3.4", "5.6.7.8"))
+	// the last hop which isn't a trusted proxy is the client
+	m.Equal("198.51.100.7", ip("10.0.0.2:1234", "1.2.3.4, 198.51.100.7, 10.0.0.3", ""))
+	m.Equal("198.51.100.7", ip("192.168.1.1:1234", "", "198.51.100.7"))
+	m.Equal("192.168.1.2:1234", ip("192.168.1.2:1234", "1.2.3.4", ""))
+
+	m.cfg.MIDDLEWARE.TRUSTED_PROXIES = []string{"10.0.0.0/33"}
+	_, err := routers.Middlewares(m.cfg, zap.NewNop().Sugar())
+	m.Error(err)
+}
+
+func (m *MiddlewareTestSuite) TestCORS() {
+	m.cfg.MIDDLEWARE.CORS.ENABLED = true
+	m.cfg.MIDDLEWARE.CORS.ALLOWED_ORIGINS = []string{"https://example.com"}
+	r := m.router()
+
+	req := httptest.NewRequest("OPTIONS", "/json", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	resp := m.serve(r, req)
+	resp.Body.Close()
+	m.Equal("https://example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	req = httptest.NewRequest("GET", "/json", nil)
+	req.Header.Set("Origin", "https://other.com")
+	resp = m.serve(r, req)
+	resp.Body.Close()
+	m.Empty(resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func (m *MiddlewareTestSuite) TestCompression() {
+	r := m.router()
+
+	req := httptest.NewRequest("GET", "/json", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	resp := m.serve(r, req)
+	defer resp.Body.Close()
+	m.Equal("br", resp.Header.Get("Content-Encoding"))
+
+	content, err := io.ReadAll(brotli.NewReader(resp.Body))
+	m.Require().NoError(err)
+	m.Contains(string(content), `"items"`)
+
+	req = httptest.NewRequest("GET", "/json", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp = m.serve(r, req)
+	defer resp.Body.Close()
+	m.Equal("gzip", resp.Header.Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(resp.Body)
+	m.Require().NoError(err)
+	content, err = io.ReadAll(reader)
+	m.Require().NoError(err)
+	m.Contains(string(content), `"items"`)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"errors"
+	"exampleproj/internal/pagination"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationTestSuite struct {
+	suite.Suite
+	spec pagination.Spec
+}
+
+func TestPaginationTestSuite(t *testing.T) {
+	suite.Run(t, new(PaginationTestSuite))
+}
+
+func (p *PaginationTestSuite) SetupTest() {
+	p.spec = pagination.Spec{Sorts: []string{"name", "id"}, Filters: []string{"name"}}
+}
+
+func (p *PaginationTestSuite) TestDefaults() {
+	params, err := p.spec.ParseQuery(url.Values{"other": {"x"}})
+	p.Require().NoError(err)
+	p.Equal(pagination.DefaultLimit, params.Limit)
+	p.Equal(pagination.Sort{Field: "name"}, params.Sort)
+	p.Nil(params.After)
+	p.Empty(params.Filters)
+	p.Equal(int64(pagination.DefaultLimit+1), params.FetchLimit())
+}
+
+func (p *PaginationTestSuite) TestNextPage() {
+	params, err := p.spec.ParseQuery(url.Values{"limit": {"2"}, "sort": {"-name"}, "name": {"Jo"}})
+	p.Require().NoError(err)
+	p.Equal(pagination.Sort{Field: "name", Desc: true}, params.Sort)
+
+	type item struct {
+		name string
+		id   int32
+	}
+	rows := []item{
+		{"Joe", 3},
+		{"Joan", 1},
+		{"Jo", 2},
+	}
+	page := pagination.NewPage(rows, params, func(i item) (string, int32) { return i.name, i.id })
+
+	p.Equal(rows[:2], page.Items)
+	p.Require().NotNil(page.NextCursor())
+	p.Contains(page.Link("/users"), `rel="next"`)
+
+	// the next params carry the position of the last item
+	next, err := p.spec.ParseQuery(page.Next.Query())
+	p.Require().NoError(err)
+	p.Equal("Joan", next.AfterValue())
+	p.Equal(int32(1), next.AfterID())
+	p.Equal(map[string]string{"name": "Jo"}, next.Filters)
+
+	last := pagination.NewPage(rows[2:], next, func(i item) (string, int32) { return i.name, i.id })
+	p.Nil(last.NextCursor())
+	p.Empty(last.Link("/users"))
+}
+
+func (p *PaginationTestSuite) TestInvalidParams() {
+	cursor := pagination.Cursor{Sort: "name", Value: "Joe", ID: 3}.Encode()
+
+	cases := map[string]url.Values{
+		"limit":  {"limit": {"0"}},
+		"sort":   {"sort": {"email"}},
+		"cursor": {"sort": {"id"}, "cursor": {cursor}},
+	}
+	for param, query := range cases {
+		_, err := p.spec.ParseQuery(query)
+
+		var perr *pagination.ParamError
+		p.Require().True(errors.As(err, &perr), param)
+		p.Equal(param, perr.Param)
+	}
+
+	_, err := p.spec.ParseQuery(url.Values{"cursor": {"%%%"}})
+	p.ErrorIs(err, pagination.ErrInvalidCursor)
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	cfg           *config.Config
+	mr            *miniredis.Miniredis
+	rdb           *redis.Client
+	authenticator *auth.Authenticator
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+func (l *RateLimitTestSuite) SetupTest() {
+	l.mr = miniredis.RunT(l.T())
+	l.rdb = redis.NewClient(&redis.Options{Addr: l.mr.Addr()})
+
+	l.cfg = config.NewConfig(config.NewViper(nil))
+	l.cfg.RATE_LIMIT.LIMIT = "2/1m"
+	l.cfg.RATE_LIMIT.ROUTES = []string{"/auth=1/1m", "/health=0"}
+	authenticator, err := auth.NewAuthenticator(l.cfg, l.rdb)
+	l.Require().NoError(err)
+	l.authenticator = authenticator
+}
+
+func (l *RateLimitTestSuite) TearDownTest() {
+	l.rdb.Close()
+}
+
+// router serves every path behind the authenticator and the limiter of store
+func (l *RateLimitTestSuite) router(store ratelimit.Store) *chi.Mux {
+	limiter, err := ratelimit.NewLimiter(l.cfg, store, zap.NewNop().Sugar())
+	l.Require().NoError(err)
+
+	r := chi.NewRouter()
+	r.Use(l.authenticator.Middleware, limiter.Middleware)
+	r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	return r
+}
+
+func (l *RateLimitTestSuite) get(r *chi.Mux, path, ip, token string) *http.Response {
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Result()
+}
+
+// stores runs f with both stores
+func (l *RateLimitTestSuite) stores(f func(store ratelimit.Store)) {
+	stores := map[string]ratelimit.Store{
+		"redis":  ratelimit.NewRedisStore(l.rdb),
+		"memory": ratelimit.NewMemoryStore(),
+	}
+	for name, store := range stores {
+		l.Run(name, func() {
+			l.mr.FlushAll()
+			f(store)
+		})
+	}
+}
+
+func (l *RateLimitTestSuite) TestLimitPerIP() {
+	l.stores(func(store ratelimit.Store) {
+		r := l.router(store)
+
+		resp := l.get(r, "/users", "10.0.0.1", "")
+		l.Equal(http.StatusOK, resp.StatusCode)
+		l.Equal("2", resp.Header.Get("RateLimit-Limit"))
+		l.Equal("1", resp.Header.Get("RateLimit-Remaining"))
+		l.Equal("60", resp.Header.Get("RateLimit-Reset"))
+
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+
+		resp = l.get(r, "/users", "10.0.0.1", "")
+		defer resp.Body.Close()
+		l.Equal(http.StatusTooManyRequests, resp.StatusCode)
+		l.Equal("0", resp.Header.Get("RateLimit-Remaining"))
+		l.NotEmpty(resp.Header.Get("Retry-After"))
+
+		var errResp app.MyError
+		l.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+		l.Equal(app.ErrorCodeRateLimited, errResp.Code)
+
+		// the other clients keep their own limit
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.2", "").StatusCode)
+	})
+}
+
+func (l *RateLimitTestSuite) TestLimitPerUser() {
+	pair, err := l.authenticator.Issue(context.Background(), auth.User{ID: 7, Email: "limited@test.com"})
+	l.Require().NoError(err)
+
+	l.stores(func(store ratelimit.Store) {
+		r := l.router(store)
+
+		// the user is limited whatever its ip
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", pair.AccessToken).StatusCode)
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.2", pair.AccessToken).StatusCode)
+		l.Equal(http.StatusTooManyRequests, l.get(r, "/users", "10.0.0.3", pair.AccessToken).StatusCode)
+
+		// and its ip isn't
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+	})
+}
+
+func (l *RateLimitTestSuite) TestRouteLimits() {
+	l.stores(func(store ratelimit.Store) {
+		r := l.router(store)
+
+		l.Equal(http.StatusOK, l.get(r, "/auth/login", "10.0.0.1", "").StatusCode)
+		l.Equal(http.StatusTooManyRequests, l.get(r, "/auth/refresh", "10.0.0.1", "").StatusCode)
+
+		// the default limit is counted apart
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+
+		for i := 0; i < 5; i++ {
+			resp := l.get(r, "/health", "10.0.0.1", "")
+			l.Equal(http.StatusOK, resp.StatusCode)
+			l.Empty(resp.Header.Get("RateLimit-Limit"))
+		}
+	})
+}
+
+func (l *RateLimitTestSuite) TestSlidingWindow() {
+	l.cfg.RATE_LIMIT.LIMIT = "1/200ms"
+
+	l.stores(func(store ratelimit.Store) {
+		r := l.router(store)
+
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+		l.Equal(http.StatusTooManyRequests, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+
+		time.Sleep(250 * time.Millisecond)
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+	})
+}
+
+func (l *RateLimitTestSuite) TestStoreFailureLetsThrough() {
+	r := l.router(ratelimit.NewRedisStore(l.rdb))
+	l.mr.Close()
+
+	for i := 0; i < 3; i++ {
+		l.Equal(http.StatusOK, l.get(r, "/users", "10.0.0.1", "").StatusCode)
+	}
+}
+
+func (l *RateLimitTestSuite) TestInvalidConfig() {
+	for _, limit := range []string{"100", "x/1m", "10/forever"} {
+		l.cfg.RATE_LIMIT.LIMIT = limit
+		_, err := ratelimit.NewLimiter(l.cfg, ratelimit.NewMemoryStore(), zap.NewNop().Sugar())
+		l.Error(err, limit)
+	}
+
+	l.cfg.RATE_LIMIT.STORE = "etcd"
+	_, err := ratelimit.NewStore(l.cfg, l.rdb)
+	l.Error(err)
+}
@@ -0,0 +1,28 @@
+package tests
+
+import (
+	"context"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+)
+
+// NewTestRedis provides a redis client backed by an in-process miniredis
+func NewTestRedis(lc fx.Lifecycle) (*redis.Client, *miniredis.Miniredis, error) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			defer mr.Close()
+			return rdb.Close()
+		},
+	})
+
+	return rdb, mr, nil
+}
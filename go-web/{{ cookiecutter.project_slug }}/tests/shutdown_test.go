@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"exampleproj/config"
+	"exampleproj/internal/app"
+	"exampleproj/internal/health"
+	"exampleproj/internal/shutdown"
+	"exampleproj/routers/handlers"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type ShutdownTestSuite struct {
+	suite.Suite
+	cfg         *config.Config
+	logs        *observer.ObservedLogs
+	logger      *zap.SugaredLogger
+	coordinator *shutdown.Coordinator
+}
+
+func TestShutdownTestSuite(t *testing.T) {
+	suite.Run(t, new(ShutdownTestSuite))
+}
+
+func (s *ShutdownTestSuite) SetupTest() {
+	s.cfg = config.NewConfig(config.NewViper(nil))
+	s.cfg.SHUTDOWN.DRAIN_DELAY = 0
+	s.cfg.SHUTDOWN.TIMEOUT = time.Second
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	s.logs = logs
+	s.logger = zap.New(core).Sugar()
+	s.coordinator = shutdown.NewCoordinator(s.cfg, s.logger)
+}
+
+func (s *ShutdownTestSuite) TestPhasesRunInOrder() {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	s.coordinator.Register(shutdown.PhaseWorkers, "worker", record("workers"))
+	s.coordinator.Register(shutdown.PhaseConnections, "hub", record("connections"))
+	s.coordinator.Register(shutdown.PhaseHTTP, "server", record("http"))
+	s.coordinator.Register(shutdown.PhaseDrain, "readiness", record("drain"))
+
+	s.NoError(s.coordinator.Shutdown(context.Background()))
+	s.Equal([]string{"drain", "http", "connections", "workers"}, order)
+
+	// every phase is logged
+	var phases []string
+	for _, entry := range s.logs.FilterMessage("shutdown phase completed").All() {
+		phases = append(phases, fmt.Sprint(entry.ContextMap()["phase"]))
+	}
+	s.Equal([]string{"drain", "http", "connections", "workers"}, phases)
+	s.Equal(1, s.logs.FilterMessage("shutdown completed").Len())
+
+	// the shutdown runs once
+	s.NoError(s.coordinator.Shutdown(context.Background()))
+	s.Len(order, 4)
+}
+
+func (s *ShutdownTestSuite) TestDrainDelay() {
+	s.cfg.SHUTDOWN.DRAIN_DELAY = 100 * time.Millisecond
+
+	// nothing to drain, no delay
+	start := time.Now()
+	coordinator := shutdown.NewCoordinator(s.cfg, s.logger)
+	coordinator.Register(shutdown.PhaseWorkers, "worker", func(context.Context) error { return nil })
+	s.NoError(coordinator.Shutdown(context.Background()))
+	s.Less(time.Since(start), 100*time.Millisecond)
+
+	start = time.Now()
+	coordinator = shutdown.NewCoordinator(s.cfg, s.logger)
+	coordinator.Register(shutdown.PhaseDrain, "readiness", func(context.Context) error { return nil })
+	s.NoError(coordinator.Shutdown(context.Background()))
+	s.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+}
+
+func (s *ShutdownTestSuite) TestDeadline() {
+	s.cfg.SHUTDOWN.TIMEOUT = 50 * time.Millisecond
+	coordinator := shutdown.NewCoordinator(s.cfg, s.logger)
+
+	coordinator.Register(shutdown.PhaseHTTP, "stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	workersRan := false
+	coordinator.Register(shutdown.PhaseWorkers, "worker", func(ctx context.Context) error {
+		workersRan = true
+		return nil
+	})
+
+	start := time.Now()
+	err := coordinator.Shutdown(context.Background())
+	s.Less(time.Since(start), time.Second)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.True(workersRan)
+	s.Equal(1, s.logs.FilterMessage("shutdown hook failed").Len())
+}
+
+func (s *ShutdownTestSuite) TestHTTPServerFinishesInFlightRequests() {
+	s.cfg.App.Addr = "127.0.0.1"
+	s.cfg.App.Port = freePort(s.T())
+
+	started := make(chan struct{})
+	r := chi.NewRouter()
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	registry := health.NewRegistry(s.cfg, nil)
+	lc := fxtest.NewLifecycle(s.T())
+	server, err := app.NewHTTPServer(lc, s.logger, s.cfg, r, s.coordinator, registry)
+	s.Require().NoError(err)
+	lc.RequireStart()
+
+	url := "http://" + server.Addr + "/slow"
+	type result struct {
+		body string
+		err  error
+	}
+	done := make(chan result)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		done <- result{string(body), err}
+	}()
+	<-started
+
+	s.NoError(s.coordinator.Shutdown(context.Background()))
+	s.False(registry.Readiness(context.Background()).OK())
+
+	res := <-done
+	s.NoError(res.err)
+	s.Equal("done", res.body)
+
+	// the server doesn't accept anymore
+	_, err = http.Get(url)
+	s.Error(err)
+	lc.RequireStop()
+}
+
+func (s *ShutdownTestSuite) TestWebsocketClientsGetACloseFrame() {
+	lc := fxtest.NewLifecycle(s.T())
+	ws := handlers.NewWebsocketHandler(lc, nil, s.coordinator, app.NewHub())
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	r := chi.NewRouter()
+	ws.RegisterRoute(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	s.Require().NoError(err)
+	defer conn.Close()
+
+	// the client answers the close frame, which lets the hub go
+	closed := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				closed <- err
+				return
+			}
+		}
+	}()
+
+	s.NoError(s.coordinator.Shutdown(context.Background()))
+
+	var closeErr *websocket.CloseError
+	s.Require().True(errors.As(<-closed, &closeErr))
+	s.Equal(websocket.CloseGoingAway, closeErr.Code)
+}
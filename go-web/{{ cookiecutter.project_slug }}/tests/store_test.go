@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"exampleproj/config"
+	"exampleproj/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	store db.Store
+	fxApp *fx.App
+}
+
+func (s *StoreTestSuite) SetupSuite() {
+	s.fxApp = fx.New(
+		fx.Provide(config.NewViper),
+		fx.Provide(config.NewConfig),
+		fx.Provide(NewTestStore),
+		fx.Populate(&s.store),
+	)
+
+	if err := s.fxApp.Start(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func (s *StoreTestSuite) TearDownSuite() {
+	s.fxApp.Stop(context.Background())
+}
+
+func (s *StoreTestSuite) TestExecTxCommit() {
+	ctx := context.Background()
+
+	var user db.User
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Name:         "committed",
+			Email:        "committed@test.com",
+			PasswordHash: "hash",
+		})
+		return err
+	})
+	s.NoError(err)
+
+	found, err := s.store.GetUser(ctx, user.ID)
+	s.NoError(err)
+	s.Equal("committed", found.Name)
+}
+
+func (s *StoreTestSuite) TestExecTxRollback() {
+	ctx := context.Background()
+	errComposer := errors.New("composer failed")
+
+	var user db.User
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Name:         "rolled back",
+			Email:        "rolledback@test.com",
+			PasswordHash: "hash",
+		})
+		if err != nil {
+			return err
+		}
+		return errComposer
+	})
+	s.ErrorIs(err, errComposer)
+
+	_, err = s.store.GetUser(ctx, user.ID)
+	s.ErrorIs(err, pgx.ErrNoRows)
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
@@ -1,22 +1,29 @@
 package tests
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"exampleproj/config"
 	"exampleproj/db"
 	"exampleproj/internal/app"
+	"exampleproj/internal/auth"
+	"exampleproj/internal/idempotency"
 	"exampleproj/routers"
 	"exampleproj/routers/handlers"
-	"bytes"
-	"context"
-	"encoding/json"
+	"exampleproj/routers/schemas"
 	"fmt"
 	"io"
+	"math/rand"
+	"net/http"
 	"net/http/httptest"
-	"os"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/fx"
 )
@@ -24,96 +31,37 @@ import (
 type UserHandlerTestSuite struct {
 	suite.Suite
 	r     *chi.Mux
+	store db.Store
 	fxApp *fx.App
 }
 
-func (u *UserHandlerTestSuite) NewTestDB(lc fx.Lifecycle, cfg *config.Config) *pgx.Conn {
-	test_db := cfg.DB.NAME
-	ctx := context.Background()
-	cfg.DB.NAME = "postgres"
-
-	masterConn, err := pgx.Connect(ctx, db.GetPostgresqlDSN(cfg))
-	if err != nil {
-		panic(err)
-	}
-
-	_, err = masterConn.Exec(ctx, "create database "+test_db)
-	if err != nil {
-		panic(err)
-	}
-
-	cfg.DB.NAME = test_db
-
-	conn, err := pgx.Connect(ctx, db.GetPostgresqlDSN(cfg))
-	if err != nil {
-		panic(err)
-	}
-
-	lc.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-
-			if err := conn.Close(ctx); err != nil {
-				return err
-			}
-
-			if _, err := masterConn.Exec(ctx, "drop database "+test_db); err != nil {
-				return err
-			}
-
-			return masterConn.Close(ctx)
-		},
-	})
-
-	return conn
-}
-
 func (u *UserHandlerTestSuite) SetupSuite() {
-	// temporarily hardcode the test database name
-	os.Setenv("DB_NAME", "test_db")
-
 	u.fxApp = fx.New(
 		fx.Provide(config.NewViper),
 		fx.Provide(config.NewConfig),
 		fx.Provide(app.NewLogger),
-		fx.Provide(u.NewTestDB),
+		fx.Provide(NewTestStore),
+		fx.Provide(NewTestRedis),
+		auth.Module,
+		idempotency.Module,
 		fx.Provide(
 			fx.Annotate(
 				routers.NewRouter,
-				fx.ParamTags(`group:"handlers"`),
+				fx.ParamTags(`group:"handlers"`, `optional:"true"`, `optional:"true"`, `optional:"true"`, `optional:"true"`),
 			),
-			routers.AsRoute(handlers.NewUserHandler)),
-		fx.Invoke(func(r *chi.Mux, cfg *config.Config) {
+			handlers.NewUserHandler,
+			handlers.NewAuthorHandler,
+			handlers.NewAuthHandler,
+			routers.AsRoute(handlers.NewAPI)),
+		fx.Invoke(func(r *chi.Mux) {
 			u.r = r
-
-			engine := cfg.DB.ENGINE
-			var dsn string
-
-			if engine == config.SQLite {
-				dsn = "sqlite://" + cfg.DB.NAME
-			}
-
-			if engine == config.Postgres {
-				dsn = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?search_path=public&sslmode=disable",
-					cfg.DB.USER,
-					cfg.DB.PASSWORD,
-					cfg.DB.HOST,
-					cfg.DB.PORT,
-					cfg.DB.NAME,
-				)
-			}
-
-			migrations, err := db.Migrate(dsn)
-			if err != nil {
-				panic(err)
-			}
-
-			u.T().Logf("Applied migrations: %d", len(migrations.Applied))
-
 		}),
+		fx.Populate(&u.store),
 	)
 
-	u.fxApp.Start(context.Background())
-
+	if err := u.fxApp.Start(context.Background()); err != nil {
+		panic(err)
+	}
 }
 
 func (u *UserHandlerTestSuite) TearDownSuite() {
@@ -125,16 +73,17 @@ func (u *UserHandlerTestSuite) TestCreateUserWithValidBody() {
 	"name": "John Doe",
 	"email": "song@test.com",
 	"password": "!@SDGsjfe",
-	"password_repeat": "!@SDGsjfe"
+	"repeated_password": "!@SDGsjfe"
 	}`)
 	req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	u.r.ServeHTTP(w, req)
 
 	// read the response
 	resp := w.Result()
 	defer resp.Body.Close()
-	u.Equal(200, resp.StatusCode)
+	u.Equal(http.StatusCreated, resp.StatusCode)
 
 	content, _ := io.ReadAll(resp.Body)
 
@@ -144,6 +93,44 @@ func (u *UserHandlerTestSuite) TestCreateUserWithValidBody() {
 	}
 
 	u.Equal("John Doe", res.Name)
+
+	user, err := u.store.GetUserByEmail(context.Background(), "song@test.com")
+	u.NoError(err)
+	u.NotEqual("!@SDGsjfe", user.PasswordHash)
+	u.True(app.CheckPasswordHash("!@SDGsjfe", user.PasswordHash))
+}
+
+func (u *UserHandlerTestSuite) TestCreateUserWithMismatchedPasswords() {
+	resp, content := u.do("POST", "/users", []byte(`{
+	"name": "John Doe",
+	"email": "mismatch@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfx"
+	}`))
+	u.Equal(400, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeInvalidPassword, errResp.Code)
+}
+
+func (u *UserHandlerTestSuite) TestCreateUserWithDuplicateEmail() {
+	body := []byte(`{
+	"name": "John Doe",
+	"email": "duplicate@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`)
+
+	resp, _ := u.do("POST", "/users", body)
+	u.Equal(http.StatusCreated, resp.StatusCode)
+
+	resp, content := u.do("POST", "/users", body)
+	u.Equal(409, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeEmailExists, errResp.Code)
 }
 
 func (u *UserHandlerTestSuite) TestCreateUserWithOutBody() {
@@ -159,9 +146,432 @@ func (u *UserHandlerTestSuite) TestCreateUserWithOutBody() {
 	content, _ := io.ReadAll(resp.Body)
 	var errResp app.MyError
 	json.Unmarshal(content, &errResp)
-	u.Equal(app.ErrorCodeUnknown, errResp.Code)
-	u.Equal("unknown error: EOF", errResp.Message)
+	u.Equal(app.ErrorCodeInvalidBody, errResp.Code)
+	u.Equal("invalid request body: request body has an error: value is required but missing", errResp.Message)
+
+}
+
+func (u *UserHandlerTestSuite) TestCreateUserWithInvalidFields() {
+	resp, content := u.do("POST", "/users", []byte(`{"name": "song", "email": "not-an-email", "password": "short", "repeated_password": "short"}`))
+	u.Equal(400, resp.StatusCode)
+
+	var errResp app.MyError
+	u.Require().NoError(json.Unmarshal(content, &errResp))
+	u.Equal(app.ErrorCodeValidation, errResp.Code)
+	u.Equal("validation failed", errResp.Message)
+	u.Require().NotNil(errResp.Errors)
+
+	tags := map[string]string{}
+	for _, fe := range *errResp.Errors {
+		tags[fe.Field] = fe.Tag
+		u.NotEmpty(fe.Message)
+	}
+	u.Equal(map[string]string{"email": "email", "password": "min"}, tags)
+}
+
+func (u *UserHandlerTestSuite) TestCreateUserAgainstSpec() {
+	resp, content := u.do("POST", "/users", []byte(`{"email": "spec@test.com", "name": ""}`))
+	u.Equal(400, resp.StatusCode)
+
+	var errResp app.MyError
+	u.Require().NoError(json.Unmarshal(content, &errResp))
+	u.Equal(app.ErrorCodeValidation, errResp.Code)
+	u.Require().NotNil(errResp.Errors)
+
+	tags := map[string]string{}
+	for _, fe := range *errResp.Errors {
+		tags[fe.Field] = fe.Tag
+	}
+	u.Equal(map[string]string{
+		"name":              "min",
+		"password":          "required",
+		"repeated_password": "required",
+	}, tags)
+
+	req := httptest.NewRequest("POST", "/users", bytes.NewBufferString(`{"name": "song"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	u.r.ServeHTTP(w, req)
+
+	u.Equal(400, w.Code)
+	u.Require().NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+	u.Equal(app.ErrorCodeInvalidBody, errResp.Code)
+}
+
+func (u *UserHandlerTestSuite) TestListUsersPagination() {
+	for _, name := range []string{"Page C", "Page A", "Page B", "Page A", "Page D"} {
+		email := strings.ToLower(strings.ReplaceAll(name, " ", "")) + strconv.Itoa(rand.Int()) + "@test.com"
+		resp, _ := u.do("POST", "/users", []byte(fmt.Sprintf(`{
+	"name": %q,
+	"email": %q,
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`, name, email)))
+		u.Require().Equal(http.StatusCreated, resp.StatusCode)
+	}
+
+	// follow the next_cursor through the pages
+	list := func(sort string) []string {
+		var names []string
+		target := "/users?limit=2&name=Page&sort=" + sort
+		for pages := 0; pages < 10; pages++ {
+			resp, content := u.do("GET", target, nil)
+			u.Require().Equal(200, resp.StatusCode, string(content))
+
+			var page schemas.UserPage
+			u.Require().NoError(json.Unmarshal(content, &page))
+			u.LessOrEqual(len(page.Items), 2)
+			for _, user := range page.Items {
+				names = append(names, user.Name)
+			}
+
+			if page.NextCursor == nil {
+				u.Empty(resp.Header.Get("Link"))
+				return names
+			}
+
+			link := resp.Header.Get("Link")
+			u.Contains(link, `rel="next"`)
+			u.Contains(link, url.QueryEscape(*page.NextCursor))
+
+			target = "/users?limit=2&name=Page&sort=" + sort + "&cursor=" + url.QueryEscape(*page.NextCursor)
+		}
+		u.FailNow("too many pages")
+		return nil
+	}
+
+	u.Equal([]string{"Page A", "Page A", "Page B", "Page C", "Page D"}, list("name"))
+	u.Equal([]string{"Page D", "Page C", "Page B", "Page A", "Page A"}, list("-name"))
+	u.Len(list("id"), 5)
+	u.Equal([]string{"Page D", "Page A", "Page B", "Page A", "Page C"}, list("-id"))
+}
 
+func (u *UserHandlerTestSuite) TestListUsersNameFilterIsLiteral() {
+	for _, name := range []string{"Like_% One", "LikeX Two", "like_% three"} {
+		email := "like" + strconv.Itoa(rand.Int()) + "@test.com"
+		resp, _ := u.do("POST", "/users", []byte(fmt.Sprintf(`{
+	"name": %q,
+	"email": %q,
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`, name, email)))
+		u.Require().Equal(http.StatusCreated, resp.StatusCode)
+	}
+
+	// the wildcards of LIKE match themselves and the case matters
+	resp, content := u.do("GET", "/users?sort=name&name="+url.QueryEscape("Like_%"), nil)
+	u.Require().Equal(200, resp.StatusCode, string(content))
+
+	var page schemas.UserPage
+	u.Require().NoError(json.Unmarshal(content, &page))
+	u.Require().Len(page.Items, 1)
+	u.Equal("Like_% One", page.Items[0].Name)
+}
+
+func (u *UserHandlerTestSuite) TestListUsersWithInvalidParams() {
+	resp, content := u.do("GET", "/users?limit=2&sort=name", nil)
+	u.Require().Equal(200, resp.StatusCode)
+
+	var page schemas.UserPage
+	u.Require().NoError(json.Unmarshal(content, &page))
+
+	cases := map[string]string{
+		"/users?cursor=garbage": "cursor",
+		"/users?limit=0":        "limit",
+		"/users?limit=101":      "limit",
+		"/users?sort=email":     "sort",
+	}
+	if page.NextCursor != nil {
+		// the cursor of another sort
+		cases["/users?sort=-name&cursor="+url.QueryEscape(*page.NextCursor)] = "cursor"
+	}
+
+	for target, param := range cases {
+		resp, content := u.do("GET", target, nil)
+		u.Equal(400, resp.StatusCode, target)
+
+		var errResp app.MyError
+		u.Require().NoError(json.Unmarshal(content, &errResp))
+		if param == "cursor" {
+			u.Equal(app.ErrorCodeInvalidParam, errResp.Code, target)
+			u.Equal("invalid parameter cursor", errResp.Message, target)
+		} else {
+			// rejected by the spec
+			u.Equal(app.ErrorCodeValidation, errResp.Code, target)
+		}
+	}
+}
+
+// TestCreateUsersConcurrently checks no request sees the state of another,
+// run it with -race
+func (u *UserHandlerTestSuite) TestCreateUsersConcurrently() {
+	const n = 16
+
+	statuses := make([]int, n)
+	names := make([]string, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			resp, content := u.do("POST", "/users", []byte(fmt.Sprintf(`{
+	"name": "user %d",
+	"email": "concurrent%d@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`, i, i)))
+			statuses[i] = resp.StatusCode
+
+			var res schemas.CreateUserResponse
+			json.Unmarshal(content, &res)
+			names[i] = res.Name
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		u.Equal(http.StatusCreated, statuses[i])
+		u.Equal(fmt.Sprintf("user %d", i), names[i])
+
+		user, err := u.store.GetUserByEmail(context.Background(), fmt.Sprintf("concurrent%d@test.com", i))
+		u.NoError(err)
+		u.Equal(fmt.Sprintf("user %d", i), user.Name)
+	}
+}
+
+func (u *UserHandlerTestSuite) TestCreateUserRetriedWithIdempotencyKey() {
+	body := []byte(`{
+	"name": "Retry Doe",
+	"email": "retry@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`)
+	post := func(body []byte) (*http.Response, []byte) {
+		req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.HeaderKey, "create-retry-doe")
+		w := httptest.NewRecorder()
+		u.r.ServeHTTP(w, req)
+		return w.Result(), w.Body.Bytes()
+	}
+
+	resp, first := post(body)
+	u.Equal(http.StatusCreated, resp.StatusCode)
+
+	// the retry gets the same response instead of a 409 on the email
+	resp, retried := post(body)
+	u.Equal(http.StatusCreated, resp.StatusCode)
+	u.Equal("true", resp.Header.Get(idempotency.HeaderReplayed))
+	u.JSONEq(string(first), string(retried))
+
+	resp, content := post([]byte(`{
+	"name": "Other Doe",
+	"email": "other-retry@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`))
+	u.Equal(http.StatusConflict, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeIdempotencyKeyReused, errResp.Code)
+
+	_, err := u.store.GetUserByEmail(context.Background(), "other-retry@test.com")
+	u.Error(err)
+}
+
+// do serves the request and returns the response with its body, the
+// optional token is sent as the bearer token
+func (u *UserHandlerTestSuite) do(method, target string, body []byte, token ...string) (*http.Response, []byte) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewBuffer(body)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token[0])
+	}
+	w := httptest.NewRecorder()
+	u.r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	content, _ := io.ReadAll(resp.Body)
+	return resp, content
+}
+
+func (u *UserHandlerTestSuite) TestUserCRUD() {
+	resp, _ := u.do("POST", "/users", []byte(`{
+	"name": "Jane Doe",
+	"email": "jane@test.com",
+	"password": "!@SDGsjfe",
+	"repeated_password": "!@SDGsjfe"
+	}`))
+	u.Equal(http.StatusCreated, resp.StatusCode)
+
+	resp, content := u.do("GET", "/users?name=Jane", nil)
+	u.Equal(200, resp.StatusCode)
+
+	var users schemas.UserPage
+	if err := json.Unmarshal(content, &users); err != nil {
+		panic(err)
+	}
+
+	var user schemas.User
+	for _, item := range users.Items {
+		if item.Name == "Jane Doe" {
+			user = item
+		}
+	}
+	u.NotZero(user.Id)
+
+	target := fmt.Sprintf("/users/%d", user.Id)
+	resp, content = u.do("GET", target, nil)
+	u.Equal(200, resp.StatusCode)
+	u.JSONEq(fmt.Sprintf(`{"id": %d, "name": "Jane Doe"}`, user.Id), string(content))
+
+	resp, _ = u.do("PATCH", target, []byte(`{"name": "Jane Roe"}`))
+	u.Equal(401, resp.StatusCode)
+
+	token := u.login("jane@test.com", "!@SDGsjfe")
+
+	resp, content = u.do("PATCH", target, []byte(`{"name": "Jane Roe"}`), token)
+	u.Equal(200, resp.StatusCode)
+	u.JSONEq(fmt.Sprintf(`{"id": %d, "name": "Jane Roe"}`, user.Id), string(content))
+
+	// deleting requires the users:delete permission
+	resp, content = u.do("DELETE", target, nil, token)
+	u.Equal(403, resp.StatusCode)
+
+	var forbidden app.MyError
+	json.Unmarshal(content, &forbidden)
+	u.Equal(app.ErrorCodeForbidden, forbidden.Code)
+
+	adminToken := u.login("admin@test.com", "!@SDGsjfe")
+	u.grantRole("admin@test.com", "admin")
+
+	resp, _ = u.do("DELETE", target, nil, adminToken)
+	u.Equal(204, resp.StatusCode)
+
+	resp, content = u.do("GET", target, nil)
+	u.Equal(404, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeUserNotFound, errResp.Code)
+}
+
+// login registers the user when needed and returns an access token
+func (u *UserHandlerTestSuite) login(email, password string) string {
+	u.do("POST", "/users", []byte(fmt.Sprintf(`{
+	"name": "login",
+	"email": %q,
+	"password": %q,
+	"repeated_password": %q
+	}`, email, password, password)))
+
+	resp, content := u.do("POST", "/auth/login", []byte(fmt.Sprintf(`{"email": %q, "password": %q}`, email, password)))
+	u.Require().Equal(200, resp.StatusCode)
+
+	var tokens schemas.TokenResponse
+	if err := json.Unmarshal(content, &tokens); err != nil {
+		panic(err)
+	}
+	return tokens.AccessToken
+}
+
+// grantRole assigns the role to the user registered with the email
+func (u *UserHandlerTestSuite) grantRole(email, role string) {
+	ctx := context.Background()
+
+	user, err := u.store.GetUserByEmail(ctx, email)
+	u.Require().NoError(err)
+
+	r, err := u.store.GetRoleByName(ctx, role)
+	u.Require().NoError(err)
+
+	err = u.store.AssignRole(ctx, db.AssignRoleParams{UserID: user.ID, RoleID: r.ID})
+	u.Require().NoError(err)
+}
+
+func (u *UserHandlerTestSuite) TestUpdateOtherUserIsForbidden() {
+	token := u.login("other@test.com", "!@SDGsjfe")
+	u.login("victim@test.com", "!@SDGsjfe")
+
+	victim, err := u.store.GetUserByEmail(context.Background(), "victim@test.com")
+	u.Require().NoError(err)
+
+	resp, content := u.do("PATCH", fmt.Sprintf("/users/%d", victim.ID), []byte(`{"name": "pwned"}`), token)
+	u.Equal(403, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeForbidden, errResp.Code)
+}
+
+func (u *UserHandlerTestSuite) TestAdminEmailsGrantTheRole() {
+	adminToken := u.login("bootstrap@test.com", "!@SDGsjfe")
+	u.login("bootstrapped@test.com", "!@SDGsjfe")
+	victim, err := u.store.GetUserByEmail(context.Background(), "bootstrapped@test.com")
+	u.Require().NoError(err)
+	target := fmt.Sprintf("/users/%d", victim.ID)
+
+	resp, _ := u.do("DELETE", target, nil, adminToken)
+	u.Equal(403, resp.StatusCode)
+
+	missing, err := auth.GrantAdmins(context.Background(), u.store, []string{"bootstrap@test.com", "unregistered@test.com"})
+	u.Require().NoError(err)
+	u.Equal([]string{"unregistered@test.com"}, missing)
+
+	// granted again on every start
+	_, err = auth.GrantAdmins(context.Background(), u.store, []string{"bootstrap@test.com"})
+	u.Require().NoError(err)
+
+	resp, _ = u.do("DELETE", target, nil, adminToken)
+	u.Equal(204, resp.StatusCode)
+}
+
+func (u *UserHandlerTestSuite) TestUserNotFound() {
+	token := u.login("notfound@test.com", "!@SDGsjfe")
+	u.grantRole("notfound@test.com", "admin")
+
+	for _, method := range []string{"GET", "PATCH", "DELETE"} {
+		resp, content := u.do(method, "/users/999999", []byte(`{"name": "nobody"}`), token)
+		u.Equal(404, resp.StatusCode, method)
+
+		var errResp app.MyError
+		json.Unmarshal(content, &errResp)
+		u.Equal(app.ErrorCodeUserNotFound, errResp.Code, method)
+	}
+}
+
+func (u *UserHandlerTestSuite) TestUserInvalidID() {
+	resp, content := u.do("GET", "/users/abc", nil)
+	u.Equal(400, resp.StatusCode)
+
+	var errResp app.MyError
+	json.Unmarshal(content, &errResp)
+	u.Equal(app.ErrorCodeInvalidParam, errResp.Code)
+	u.Equal("invalid parameter id", errResp.Message)
+}
+
+func (u *UserHandlerTestSuite) TestAuthenticationBeforeBody() {
+	// the bearerAuth security of the spec is checked before the body is read
+	for _, method := range []string{"PATCH", "DELETE"} {
+		resp, content := u.do(method, "/users/1", nil)
+		u.Equal(401, resp.StatusCode, method)
+
+		var errResp app.MyError
+		json.Unmarshal(content, &errResp)
+		u.Equal(app.ErrorCodeUnauthenticated, errResp.Code, method)
+	}
 }
 
 func TestUserHandlerTestSuite(t *testing.T) {